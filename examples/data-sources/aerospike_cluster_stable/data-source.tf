@@ -0,0 +1,19 @@
+# Wait up to 5 minutes for a 3 node cluster to form and finish migrations
+data "aerospike_cluster_stable" "ready" {
+  expected_size = 3
+  wait_timeout  = 300
+}
+
+resource "aerospike_config" "service" {
+  context = "service"
+  parameters = {
+    "migrate-threads" = "2"
+  }
+
+  lifecycle {
+    precondition {
+      condition     = data.aerospike_cluster_stable.ready.stable
+      error_message = "The cluster is not stable: ${data.aerospike_cluster_stable.ready.reason}"
+    }
+  }
+}
@@ -0,0 +1,16 @@
+data "aerospike_config_compliance" "test" {
+  context   = "namespace"
+  namespace = "test"
+  desired = {
+    "default-ttl"                = "0"
+    "nsup-period"                = "120"
+    "stop-writes-sys-memory-pct" = "90"
+  }
+}
+
+check "namespace_compliant" {
+  assert {
+    condition     = data.aerospike_config_compliance.test.compliant
+    error_message = "Drifted parameters: ${join(", ", [for d in data.aerospike_config_compliance.test.differences : "${d.parameter} on ${d.node}"])}"
+  }
+}
@@ -0,0 +1,13 @@
+data "aerospike_edition" "cluster" {}
+
+resource "aerospike_role" "app" {
+  role_name  = "app"
+  privileges = [{ privilege = "read-write", namespace = "aerospike" }]
+
+  lifecycle {
+    precondition {
+      condition     = data.aerospike_edition.cluster.security_enabled
+      error_message = "Security must be enabled in the cluster"
+    }
+  }
+}
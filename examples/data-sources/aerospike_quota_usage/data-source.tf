@@ -0,0 +1,9 @@
+data "aerospike_quota_usage" "app" {
+  name_prefix = "app-"
+}
+
+output "app_read_usage" {
+  value = {
+    for u in data.aerospike_quota_usage.app.users : u.user_name => "${u.read_tps + u.read_rps}/${u.read_quota}"
+  }
+}
@@ -0,0 +1,10 @@
+data "aerospike_racks" "test" {
+  namespace = "test"
+}
+
+check "test_spans_racks" {
+  assert {
+    condition     = length(data.aerospike_racks.test.racks) > 1
+    error_message = "Namespace test is not spread over more than one rack"
+  }
+}
@@ -0,0 +1,9 @@
+data "aerospike_roles" "app_roles" {
+  name_regex = "^app-.*-rw$"
+}
+
+import {
+  for_each = toset(data.aerospike_roles.app_roles.role_names)
+  to       = aerospike_role.app[each.key]
+  id       = each.key
+}
@@ -0,0 +1,21 @@
+data "aerospike_security_export" "primary" {}
+
+resource "local_file" "security_backup" {
+  filename = "security-export.json"
+  content  = data.aerospike_security_export.primary.json
+}
+
+# Recreate the roles on a DR cluster
+locals {
+  export = jsondecode(data.aerospike_security_export.primary.json)
+}
+
+resource "aerospike_role" "dr" {
+  provider    = aerospike.dr
+  for_each    = { for r in local.export.roles : r.role_name => r }
+  role_name   = each.value.role_name
+  privileges  = each.value.privileges
+  white_list  = each.value.white_list
+  read_quota  = each.value.read_quota
+  write_quota = each.value.write_quota
+}
@@ -0,0 +1,15 @@
+data "aerospike_security_report" "report" {}
+
+check "roles_have_whitelists" {
+  assert {
+    condition     = length(data.aerospike_security_report.report.roles_without_whitelist) == 0
+    error_message = "Roles without a whitelist: ${join(", ", data.aerospike_security_report.report.roles_without_whitelist)}"
+  }
+}
+
+check "no_unexpected_admins" {
+  assert {
+    condition     = alltrue([for u in data.aerospike_security_report.report.admin_users : contains(["admin"], u.user_name)])
+    error_message = "Unexpected admin users"
+  }
+}
@@ -0,0 +1,11 @@
+data "aerospike_set" "sessions" {
+  namespace = "test"
+  set       = "sessions"
+}
+
+check "sessions_capacity" {
+  assert {
+    condition     = data.aerospike_set.sessions.stop_writes_count == 0 || data.aerospike_set.sessions.objects < data.aerospike_set.sessions.stop_writes_count * 0.8
+    error_message = "Set sessions is above 80% of its stop-writes-count"
+  }
+}
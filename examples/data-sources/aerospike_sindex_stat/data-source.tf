@@ -0,0 +1,11 @@
+data "aerospike_sindex_stat" "users_age" {
+  namespace = "test"
+  name      = "idx_users_age"
+}
+
+check "users_age_index" {
+  assert {
+    condition     = data.aerospike_sindex_stat.users_age.ready
+    error_message = "Index idx_users_age is ${data.aerospike_sindex_stat.users_age.state}, ${data.aerospike_sindex_stat.users_age.load_pct}% built"
+  }
+}
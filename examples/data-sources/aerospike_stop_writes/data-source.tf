@@ -0,0 +1,19 @@
+data "aerospike_stop_writes" "test" {
+  namespace = "test"
+}
+
+resource "aerospike_record" "example" {
+  namespace = "test"
+  set       = "settings"
+  key       = "example"
+  bins = {
+    value = "1"
+  }
+
+  lifecycle {
+    precondition {
+      condition     = !data.aerospike_stop_writes.test.stop_writes
+      error_message = "Namespace test is in stop-writes: ${join(", ", data.aerospike_stop_writes.test.reasons)}"
+    }
+  }
+}
@@ -0,0 +1,9 @@
+data "aerospike_users" "service_accounts" {
+  name_prefix = "svc-"
+}
+
+import {
+  for_each = toset(data.aerospike_users.service_accounts.user_names)
+  to       = aerospike_user.service_account[each.key]
+  id       = each.key
+}
@@ -0,0 +1,4 @@
+ephemeral "aerospike_temporary_user" "migration" {
+  name_prefix = "migration-"
+  roles       = ["read-write"]
+}
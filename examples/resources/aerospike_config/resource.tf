@@ -0,0 +1,22 @@
+resource "aerospike_config" "service" {
+  context = "service"
+  parameters = {
+    "migrate-threads" = "2"
+  }
+}
+
+resource "aerospike_config" "namespace" {
+  context   = "namespace"
+  namespace = "aerospike"
+  parameters = {
+    "nsup-period" = "60"
+  }
+}
+
+resource "aerospike_config" "heartbeat" {
+  context = "network"
+  parameters = {
+    "heartbeat.interval" = "150"
+    "heartbeat.timeout"  = "10"
+  }
+}
@@ -0,0 +1,6 @@
+resource "aerospike_config_cluster" "cluster" {
+  cluster_name = "prod-east"
+  parameters = {
+    "min-cluster-size" = "3"
+  }
+}
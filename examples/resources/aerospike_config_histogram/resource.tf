@@ -0,0 +1,5 @@
+resource "aerospike_config_histogram" "test" {
+  namespace = "test"
+  read      = true
+  write     = true
+}
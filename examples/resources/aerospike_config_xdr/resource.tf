@@ -0,0 +1,7 @@
+resource "aerospike_config_xdr" "xdr" {
+  src_id       = 1
+  trace_sample = 0
+  parameters = {
+    "max-throughput" = "100000"
+  }
+}
@@ -0,0 +1,8 @@
+# Re-run the sindex garbage collector tuning whenever the rollout version changes
+resource "aerospike_info_command" "sindex_gc" {
+  commands  = ["set-config:context=service;sindex-gc-period=30"]
+  all_nodes = true
+  triggers = {
+    rollout = "2024-06"
+  }
+}
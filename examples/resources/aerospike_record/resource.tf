@@ -0,0 +1,9 @@
+resource "aerospike_record" "feature_flags" {
+  namespace = "aerospike"
+  set       = "config"
+  key       = "feature-flags"
+  bins = {
+    new_checkout   = "true"
+    schema_version = "12"
+  }
+}
@@ -0,0 +1,8 @@
+resource "aerospike_records" "countries" {
+  namespace = "aerospike"
+  set       = "countries"
+  records = {
+    il = { name = "Israel", currency = "ILS" }
+    us = { name = "United States", currency = "USD" }
+  }
+}
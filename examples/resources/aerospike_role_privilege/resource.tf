@@ -0,0 +1,6 @@
+resource "aerospike_role_privilege" "app_orders" {
+  role_name = "app"
+  privilege = "read-write"
+  namespace = "aerospike"
+  set       = "orders"
+}
@@ -2,4 +2,15 @@ resource "aerospike_user" "test2" {
   user_name = "test2"
   password  = "test24"
   roles     = ["role21", "role22"]
-}
\ No newline at end of file
+}
+
+# The same user on an XDR destination cluster
+resource "aerospike_user" "test2_dr" {
+  user_name = "test2"
+  password  = "test24"
+  roles     = ["role21", "role22"]
+
+  connection = {
+    host = "dr.example.com"
+  }
+}
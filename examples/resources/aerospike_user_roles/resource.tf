@@ -0,0 +1,4 @@
+resource "aerospike_user_roles" "app" {
+  user_name = "service-account"
+  roles     = ["app-read", "app-write"]
+}
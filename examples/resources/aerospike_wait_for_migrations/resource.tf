@@ -0,0 +1,17 @@
+# wait for the partitions to settle after nodes are added, before tuning the namespace
+resource "aerospike_wait_for_migrations" "after_scale_out" {
+  timeout = 1800
+  triggers = {
+    node_count = "4"
+  }
+}
+
+resource "aerospike_config" "eviction" {
+  context   = "namespace"
+  namespace = "test"
+  parameters = {
+    "evict-tenths-pct" = "5"
+  }
+
+  depends_on = [aerospike_wait_for_migrations.after_scale_out]
+}
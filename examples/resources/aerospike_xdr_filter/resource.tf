@@ -0,0 +1,5 @@
+resource "aerospike_xdr_filter" "test" {
+  dc         = "dc1"
+  namespace  = "test"
+  expression = "kxGRSCr6"
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// astoolsCluster holds the connection settings of a cluster section in an Aerospike tools configuration file
+// (astools.conf), as used by asadm and asinfo.
+type astoolsCluster struct {
+	Host      string `toml:"host"`
+	Port      int64  `toml:"port"`
+	TLSName   string `toml:"tls-name"`
+	User      string `toml:"user"`
+	Password  string `toml:"password"`
+	TLSEnable bool   `toml:"tls-enable"`
+	TLSCAFile string `toml:"tls-cafile"`
+}
+
+// readAstoolsConfig reads the [cluster] section, or [cluster_<instance>] when an instance is given, from an Aerospike
+// tools configuration file. Only the first host of a host list is used. Passwords given as env:, env-b64:, b64: or
+// file: are resolved.
+func readAstoolsConfig(fileName, instance string) (astoolsCluster, error) {
+	var cluster astoolsCluster
+	var sections map[string]toml.Primitive
+
+	fileName, err := expandHome(fileName)
+	if err != nil {
+		return cluster, err
+	}
+
+	md, err := toml.DecodeFile(fileName, &sections)
+	if err != nil {
+		return cluster, err
+	}
+
+	sectionName := "cluster"
+	if instance != "" {
+		sectionName += "_" + instance
+	}
+
+	section, ok := sections[sectionName]
+	if !ok {
+		return cluster, fmt.Errorf("section [%s] not found in %s", sectionName, fileName)
+	}
+
+	err = md.PrimitiveDecode(section, &cluster)
+	if err != nil {
+		return cluster, err
+	}
+
+	cluster.Password, err = resolveAstoolsPassword(cluster.Password)
+	if err != nil {
+		return cluster, fmt.Errorf("invalid password in %s: %w", fileName, err)
+	}
+
+	// hosts are given as a comma separated list of host, host:port or host:tls-name:port
+	firstHost, _, _ := strings.Cut(cluster.Host, ",")
+	parts := strings.Split(strings.TrimSpace(firstHost), ":")
+	cluster.Host = parts[0]
+	switch len(parts) {
+	case 2:
+		cluster.Port, err = strconv.ParseInt(parts[1], 10, 64)
+	case 3:
+		cluster.TLSName = parts[1]
+		cluster.Port, err = strconv.ParseInt(parts[2], 10, 64)
+	}
+	if err != nil {
+		return cluster, fmt.Errorf("invalid host %q in %s: %w", firstHost, fileName, err)
+	}
+
+	return cluster, nil
+}
+
+// resolveAstoolsPassword returns the password of an astools.conf password setting, which is either the password or
+// where to read it from: env:<variable>, env-b64:<variable> with a base64 value, b64:<base64 value> or file:<path>.
+func resolveAstoolsPassword(password string) (string, error) {
+	kind, value, ok := strings.Cut(password, ":")
+	if !ok {
+		return password, nil
+	}
+
+	switch kind {
+	case "env", "env-b64":
+		env, set := os.LookupEnv(value)
+		if !set {
+			return "", fmt.Errorf("environment variable %s is not set", value)
+		}
+		if kind == "env" {
+			return env, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(env)
+		if err != nil {
+			return "", fmt.Errorf("environment variable %s is not base64: %w", value, err)
+		}
+		return string(decoded), nil
+	case "b64":
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("b64 password is not base64: %w", err)
+		}
+		return string(decoded), nil
+	case "file":
+		return readPasswordFile(value)
+	}
+
+	// a password that happens to contain a colon
+	return password, nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory.
+func expandHome(fileName string) (string, error) {
+	if fileName != "~" && !strings.HasPrefix(fileName, "~/") {
+		return fileName, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(fileName, "~")), nil
+}
+
+// resolvePath expands a leading ~ and makes fileName absolute. Relative paths are relative to the Terraform working
+// directory, which terraform runs the provider in.
+func resolvePath(fileName string) (string, error) {
+	fileName, err := expandHome(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(fileName)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadAstoolsConfig(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(dir, "astools.conf")
+	conf := `
+[cluster]
+host = "10.0.0.1:3000"
+user = "admin"
+password = "secret"
+
+[cluster_tls]
+host = "10.0.0.2:tls1:4333, 10.0.0.3:tls1:4333"
+tls-enable = true
+tls-cafile = "/etc/aerospike/ca.pem"
+user = "tls-user"
+password = "env:ASTOOLS_TEST_PASSWORD"
+
+[cluster_file]
+host = "db1"
+port = 3100
+password = "file:` + passwordFile + `"
+
+[cluster_b64]
+host = "db2:3000"
+password = "b64:c2VjcmV0"
+
+[cluster_unset]
+host = "db3:3000"
+password = "env:ASTOOLS_TEST_UNSET"
+`
+	if err := os.WriteFile(fileName, []byte(conf), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ASTOOLS_TEST_PASSWORD", "from-env")
+	os.Unsetenv("ASTOOLS_TEST_UNSET")
+
+	tests := []struct {
+		name     string
+		instance string
+		want     astoolsCluster
+		wantErr  bool
+	}{
+		{name: "default", want: astoolsCluster{Host: "10.0.0.1", Port: 3000, User: "admin", Password: "secret"}},
+		{name: "tls", instance: "tls", want: astoolsCluster{Host: "10.0.0.2", Port: 4333, TLSName: "tls1", User: "tls-user",
+			Password: "from-env", TLSEnable: true, TLSCAFile: "/etc/aerospike/ca.pem"}},
+		{name: "password file", instance: "file", want: astoolsCluster{Host: "db1", Port: 3100, Password: "from-file"}},
+		{name: "base64 password", instance: "b64", want: astoolsCluster{Host: "db2", Port: 3000, Password: "secret"}},
+		{name: "unset password variable", instance: "unset", wantErr: true},
+		{name: "missing instance", instance: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readAstoolsConfig(fileName, tt.instance)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("readAstoolsConfig() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readAstoolsConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readAstoolsConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// readInfoCommands are the info commands that only read the cluster. They are sent on every refresh and aren't
+// audited, every other info command is.
+var readInfoCommands = []string{"build", "cluster-stable", "edition", "feature-key", "get-config", "namespace",
+	"namespaces", "peers-clear-std", "peers-tls-std", "racks", "sets", "sindex-list", "sindex-stat", "statistics",
+	"xdr-get-filter"}
+
+// auditEntry is a line of the audit log.
+type auditEntry struct {
+	Time    string `json:"time"`
+	User    string `json:"user"`
+	Node    string `json:"node,omitempty"`
+	Command string `json:"command"`
+	Result  string `json:"result"`
+}
+
+// auditLog appends the commands that change the cluster to a file, one JSON object per line. It is shared by all
+// the connections of the provider.
+type auditLog struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// write appends entry to the audit log file, creating it if needed.
+func (a *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// audit records a command in the audit log when audit_log_file is set. node is empty for admin commands, which the
+// client sends to any node. Commands must already be redacted. A failure to write is logged, the command already ran.
+func (c *asConnection) audit(ctx context.Context, node, command, result string) {
+	if c.auditLog == nil {
+		return
+	}
+
+	entry := auditEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		User:    c.userName,
+		Node:    node,
+		Command: command,
+		Result:  result,
+	}
+	if err := c.auditLog.write(entry); err != nil {
+		tflog.Error(ctx, "unable to write the audit log: "+err.Error(), map[string]interface{}{"command": command})
+	}
+}
+
+// auditAdmin records an admin command with its result.
+func (c *asConnection) auditAdmin(ctx context.Context, command string, err error) {
+	result := "ok"
+	if err != nil {
+		result = err.Error()
+	}
+
+	c.audit(ctx, "", command, result)
+}
+
+// auditInfo records an info command and a node's response, unless the command only reads the cluster.
+func (c *asConnection) auditInfo(ctx context.Context, node, command, response string) {
+	if c.auditLog == nil || isReadInfoCommand(command) {
+		return
+	}
+
+	c.audit(ctx, node, redactInfoCommand(command), redactInfoParams(strings.TrimSpace(response)))
+}
+
+// privsToStr formats privileges for the audit log.
+func privsToStr(privileges []as.Privilege) string {
+	strs := make([]string, 0, len(privileges))
+	for _, p := range privileges {
+		strs = append(strs, privToStr(p))
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// isReadInfoCommand reports whether command only reads the cluster.
+func isReadInfoCommand(command string) bool {
+	name, _, _ := strings.Cut(infoCommandName(command), "/")
+	for _, c := range readInfoCommands {
+		if name == c {
+			return true
+		}
+	}
+
+	return false
+}
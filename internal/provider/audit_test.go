@@ -0,0 +1,67 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+)
+
+func TestAuditAdmin(t *testing.T) {
+	client := newFakeClient()
+	conn := newFakeConnection(client, "admin")
+	conn.auditLog = &auditLog{path: filepath.Join(t.TempDir(), "audit.log")}
+
+	client.users["u1"] = &as.UserRoles{User: "u1"}
+	if err := conn.getClient(context.Background()).ChangePassword(conn.adminPolicy, "u1", "secret"); err != nil {
+		t.Fatalf("ChangePassword() = %v", err)
+	}
+	if err := conn.getClient(context.Background()).DropUser(conn.adminPolicy, "u1"); err != nil {
+		t.Fatalf("DropUser() = %v", err)
+	}
+	// queries don't change the cluster and aren't audited
+	_, _ = conn.getClient(context.Background()).QueryUsers(conn.adminPolicy)
+
+	content, err := os.ReadFile(conn.auditLog.path)
+	if err != nil {
+		t.Fatalf("reading the audit log: %v", err)
+	}
+	if strings.Contains(string(content), "secret") {
+		t.Errorf("audit log contains the password: %s", content)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d lines, want 2: %s", len(lines), content)
+	}
+	var entry auditEntry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("decoding %q: %v", lines[1], err)
+	}
+	if entry.User != "admin" || entry.Command != "drop-user u1" || entry.Result != "ok" || entry.Time == "" {
+		t.Errorf("audit entry = %+v, want drop-user u1 by admin with result ok", entry)
+	}
+}
+
+func TestIsReadInfoCommand(t *testing.T) {
+	for command, want := range map[string]bool{
+		"get-config:context=service": true,
+		"namespace/test":             true,
+		"statistics":                 true,
+		"set-config:context=service;migrate-threads=2": false,
+		"recluster:":                                    false,
+		"truncate-namespace:namespace=test":             false,
+		"xdr-set-filter:dc=dc1;namespace=test;exp=kxGV": false,
+	} {
+		if got := isReadInfoCommand(command); got != want {
+			t.Errorf("isReadInfoCommand(%q) = %v, want %v", command, got, want)
+		}
+	}
+}
@@ -0,0 +1,669 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// aerospikeClient is the subset of as.ClientIfc used by the provider. Resources only depend on this interface so
+// their logic can be unit tested against a fake client.
+type aerospikeClient interface {
+	IsConnected() bool
+	Close()
+	GetNodes() []*as.Node
+
+	CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) as.Error
+	DropUser(policy *as.AdminPolicy, user string) as.Error
+	ChangePassword(policy *as.AdminPolicy, user string, password string) as.Error
+	GrantRoles(policy *as.AdminPolicy, user string, roles []string) as.Error
+	RevokeRoles(policy *as.AdminPolicy, user string, roles []string) as.Error
+	QueryUser(policy *as.AdminPolicy, user string) (*as.UserRoles, as.Error)
+	QueryUsers(policy *as.AdminPolicy) ([]*as.UserRoles, as.Error)
+
+	CreateRole(policy *as.AdminPolicy, roleName string, privileges []as.Privilege, whitelist []string, readQuota, writeQuota uint32) as.Error
+	DropRole(policy *as.AdminPolicy, roleName string) as.Error
+	GrantPrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error
+	RevokePrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error
+	SetWhitelist(policy *as.AdminPolicy, roleName string, whitelist []string) as.Error
+	SetQuotas(policy *as.AdminPolicy, roleName string, readQuota, writeQuota uint32) as.Error
+	QueryRole(policy *as.AdminPolicy, role string) (*as.Role, as.Error)
+	QueryRoles(policy *as.AdminPolicy) ([]*as.Role, as.Error)
+
+	Put(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) as.Error
+	Get(policy *as.BasePolicy, key *as.Key, binNames ...string) (*as.Record, as.Error)
+	Delete(policy *as.WritePolicy, key *as.Key) (bool, as.Error)
+	BatchOperate(policy *as.BatchPolicy, records []as.BatchRecordIfc) as.Error
+}
+
+var _ aerospikeClient = as.ClientIfc(nil)
+
+// getClient returns the current client, wrapped to log in again when the session expires. Retries and logging in
+// again stop when ctx is done. The client itself is safe for concurrent use.
+func (c *asConnection) getClient(ctx context.Context) aerospikeClient {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return &reloginClient{ctx: ctx, conn: c, client: c.client}
+}
+
+// ensureConnected re-creates the client if it lost its connection to the cluster, for example when the seed node
+// was restarted during a long apply. Creating the new client also logs in again.
+func (c *asConnection) ensureConnected(ctx context.Context) error {
+	if c.getClient(ctx).IsConnected() {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// another goroutine may have reconnected while we waited for the lock
+	if c.client.IsConnected() {
+		return nil
+	}
+
+	tflog.Warn(ctx, "connection to the Aerospike cluster was lost, reconnecting")
+
+	return c.replaceClient(ctx)
+}
+
+// relogin replaces the client after the server rejected its session, unless another goroutine already replaced
+// the failed client. It returns the client to retry with.
+func (c *asConnection) relogin(ctx context.Context, failed aerospikeClient) (aerospikeClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.client != failed {
+		return c.client, nil
+	}
+
+	tflog.Warn(ctx, "the Aerospike session expired, logging in again")
+	if err := c.replaceClient(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.client, nil
+}
+
+// replaceClient closes the client and creates a new one, which also logs in again. The caller must hold the mutex.
+func (c *asConnection) replaceClient(ctx context.Context) error {
+	c.client.Close()
+
+	newClient, err := as.CreateClientWithPolicyAndHost(c.clientType, c.clientPolicy, c.hosts...)
+	if err != nil {
+		return err
+	}
+	c.client = newClient
+
+	tflog.Info(ctx, "reconnected to the Aerospike cluster")
+
+	return nil
+}
+
+// isSessionError reports whether the server rejected a command because the session token expired or is missing.
+func isSessionError(err as.Error) bool {
+	return err != nil && err.Matches(astypes.EXPIRED_SESSION, astypes.NOT_AUTHENTICATED)
+}
+
+// reloginClient retries a command once with a new client when the session expired, e.g. during a long apply. ctx is
+// the context of the terraform operation the commands run for.
+type reloginClient struct {
+	ctx    context.Context
+	conn   *asConnection
+	client aerospikeClient
+}
+
+var _ aerospikeClient = &reloginClient{}
+
+// withRelogin runs op, and runs it again with a new client if it failed with a session error. Errors retry_policy
+// considers retryable are retried with the same client.
+func withRelogin[T any](r *reloginClient, op func(client aerospikeClient) (T, as.Error)) (T, as.Error) {
+	ctx := r.ctx
+
+	result, err := retry(ctx, r.conn.retryPolicy, func() (T, as.Error) { return op(r.client) })
+	if !isSessionError(err) {
+		return result, err
+	}
+
+	client, reloginErr := r.conn.relogin(ctx, r.client)
+	if reloginErr != nil {
+		return result, err
+	}
+	r.client = client
+
+	return retry(ctx, r.conn.retryPolicy, func() (T, as.Error) { return op(client) })
+}
+
+// withReloginErr is withRelogin for commands that only return an error.
+func withReloginErr(r *reloginClient, op func(client aerospikeClient) as.Error) as.Error {
+	_, err := withRelogin(r, func(client aerospikeClient) (struct{}, as.Error) {
+		return struct{}{}, op(client)
+	})
+
+	return err
+}
+
+// withReloginUnique is withReloginErr for commands that fail with done when they already took effect, such as
+// creating a user that exists. A retry after an error that may have hidden the command's success, such as a timeout,
+// that fails with done is a success.
+func withReloginUnique(r *reloginClient, done astypes.ResultCode, op func(client aerospikeClient) as.Error) as.Error {
+	ambiguous := false
+
+	return withReloginErr(r, func(client aerospikeClient) as.Error {
+		err := op(client)
+		if ambiguous && err != nil && err.Matches(done) {
+			return nil
+		}
+		ambiguous = r.conn.retryPolicy.retryable(err)
+
+		return err
+	})
+}
+
+func (r *reloginClient) IsConnected() bool    { return r.client.IsConnected() }
+func (r *reloginClient) Close()               { r.client.Close() }
+func (r *reloginClient) GetNodes() []*as.Node { return r.client.GetNodes() }
+
+func (r *reloginClient) CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) as.Error {
+	err := withReloginUnique(r, astypes.USER_ALREADY_EXISTS, func(client aerospikeClient) as.Error {
+		return client.CreateUser(policy, user, password, roles)
+	})
+	r.conn.auditAdmin(r.ctx, "create-user "+user+" roles="+strings.Join(roles, ","), err)
+
+	return err
+}
+
+func (r *reloginClient) DropUser(policy *as.AdminPolicy, user string) as.Error {
+	err := withReloginUnique(r, astypes.INVALID_USER, func(client aerospikeClient) as.Error {
+		return client.DropUser(policy, user)
+	})
+	r.conn.auditAdmin(r.ctx, "drop-user "+user, err)
+
+	return err
+}
+
+func (r *reloginClient) ChangePassword(policy *as.AdminPolicy, user string, password string) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.ChangePassword(policy, user, password)
+	})
+	r.conn.auditAdmin(r.ctx, "change-password "+user, err)
+
+	return err
+}
+
+func (r *reloginClient) GrantRoles(policy *as.AdminPolicy, user string, roles []string) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.GrantRoles(policy, user, roles)
+	})
+	r.conn.auditAdmin(r.ctx, "grant-roles "+user+" roles="+strings.Join(roles, ","), err)
+
+	return err
+}
+
+func (r *reloginClient) RevokeRoles(policy *as.AdminPolicy, user string, roles []string) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.RevokeRoles(policy, user, roles)
+	})
+	r.conn.auditAdmin(r.ctx, "revoke-roles "+user+" roles="+strings.Join(roles, ","), err)
+
+	return err
+}
+
+func (r *reloginClient) QueryUser(policy *as.AdminPolicy, user string) (*as.UserRoles, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) (*as.UserRoles, as.Error) {
+		return client.QueryUser(policy, user)
+	})
+}
+
+func (r *reloginClient) QueryUsers(policy *as.AdminPolicy) ([]*as.UserRoles, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) ([]*as.UserRoles, as.Error) {
+		return client.QueryUsers(policy)
+	})
+}
+
+func (r *reloginClient) CreateRole(policy *as.AdminPolicy, roleName string, privileges []as.Privilege, whitelist []string, readQuota, writeQuota uint32) as.Error {
+	err := withReloginUnique(r, astypes.ROLE_ALREADY_EXISTS, func(client aerospikeClient) as.Error {
+		return client.CreateRole(policy, roleName, privileges, whitelist, readQuota, writeQuota)
+	})
+	r.conn.auditAdmin(r.ctx, fmt.Sprintf("create-role %s privileges=%s whitelist=%s read-quota=%d write-quota=%d", roleName, privsToStr(privileges), strings.Join(whitelist, ","), readQuota, writeQuota), err)
+
+	return err
+}
+
+func (r *reloginClient) DropRole(policy *as.AdminPolicy, roleName string) as.Error {
+	err := withReloginUnique(r, astypes.INVALID_ROLE, func(client aerospikeClient) as.Error {
+		return client.DropRole(policy, roleName)
+	})
+	r.conn.auditAdmin(r.ctx, "drop-role "+roleName, err)
+
+	return err
+}
+
+func (r *reloginClient) GrantPrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.GrantPrivileges(policy, roleName, privileges)
+	})
+	r.conn.auditAdmin(r.ctx, "grant-privileges "+roleName+" privileges="+privsToStr(privileges), err)
+
+	return err
+}
+
+func (r *reloginClient) RevokePrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.RevokePrivileges(policy, roleName, privileges)
+	})
+	r.conn.auditAdmin(r.ctx, "revoke-privileges "+roleName+" privileges="+privsToStr(privileges), err)
+
+	return err
+}
+
+func (r *reloginClient) SetWhitelist(policy *as.AdminPolicy, roleName string, whitelist []string) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.SetWhitelist(policy, roleName, whitelist)
+	})
+	r.conn.auditAdmin(r.ctx, "set-whitelist "+roleName+" whitelist="+strings.Join(whitelist, ","), err)
+
+	return err
+}
+
+func (r *reloginClient) SetQuotas(policy *as.AdminPolicy, roleName string, readQuota, writeQuota uint32) as.Error {
+	err := withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.SetQuotas(policy, roleName, readQuota, writeQuota)
+	})
+	r.conn.auditAdmin(r.ctx, fmt.Sprintf("set-quotas %s read-quota=%d write-quota=%d", roleName, readQuota, writeQuota), err)
+
+	return err
+}
+
+func (r *reloginClient) QueryRole(policy *as.AdminPolicy, role string) (*as.Role, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) (*as.Role, as.Error) {
+		return client.QueryRole(policy, role)
+	})
+}
+
+func (r *reloginClient) QueryRoles(policy *as.AdminPolicy) ([]*as.Role, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) ([]*as.Role, as.Error) {
+		return client.QueryRoles(policy)
+	})
+}
+
+func (r *reloginClient) Put(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) as.Error {
+	return withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.Put(policy, key, binMap)
+	})
+}
+
+func (r *reloginClient) Get(policy *as.BasePolicy, key *as.Key, binNames ...string) (*as.Record, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) (*as.Record, as.Error) {
+		return client.Get(policy, key, binNames...)
+	})
+}
+
+func (r *reloginClient) Delete(policy *as.WritePolicy, key *as.Key) (bool, as.Error) {
+	return withRelogin(r, func(client aerospikeClient) (bool, as.Error) {
+		return client.Delete(policy, key)
+	})
+}
+
+func (r *reloginClient) BatchOperate(policy *as.BatchPolicy, records []as.BatchRecordIfc) as.Error {
+	return withReloginErr(r, func(client aerospikeClient) as.Error {
+		return client.BatchOperate(policy, records)
+	})
+}
+
+// supportsInfo reports whether info commands can be sent to the cluster. The proxy client used for Aerospike Cloud
+// doesn't expose the cluster nodes.
+func (c *asConnection) supportsInfo() bool {
+	return c.clientType != as.CTProxy
+}
+
+// detectEdition checks whether the cluster runs Enterprise Edition with security enabled.
+func (c *asConnection) detectEdition(ctx context.Context) error {
+	edition, err := c.infoAnyNode(ctx, "edition")
+	if err != nil {
+		return err
+	}
+	c.enterprise = strings.Contains(strings.ToLower(edition), "enterprise")
+	if !c.enterprise {
+		c.securityEnabled = false
+		return nil
+	}
+
+	// the security context only exists when security is enabled. securityEnabled keeps its previous value if it
+	// can't be read, so security resources aren't blocked by a transient error
+	res, err := c.infoAnyNode(ctx, getConfigCommand("security", "", ""))
+	if err != nil {
+		return err
+	}
+	c.securityEnabled = !isInfoError(res) && strings.TrimSpace(res) != ""
+
+	return nil
+}
+
+// requireSecurity returns an error diagnostic if the cluster can't manage users and roles, instead of letting the
+// security calls fail with obscure errors on Community Edition.
+func (c *asConnection) requireSecurity(typeName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	switch {
+	case !c.enterprise:
+		diags.AddError("Enterprise Edition required",
+			typeName+" requires Enterprise Edition with security enabled. The cluster runs Community Edition")
+	case !c.securityEnabled:
+		diags.AddError("Security not enabled",
+			typeName+" requires Enterprise Edition with security enabled. Enable security in the cluster configuration first")
+	}
+
+	return diags
+}
+
+// checkDestructive returns an error diagnostic if allow_destructive_operations is false, before operation runs.
+func (c *asConnection) checkDestructive(operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.blockDestructive {
+		diags.AddError("Destructive operation blocked",
+			operation+" is blocked because allow_destructive_operations is false in the provider configuration. "+
+				"Set it to true to allow it, or remove the resource from the terraform state instead")
+	}
+
+	return diags
+}
+
+// checkProviderRole returns an error diagnostic if the user the provider authenticated with holds roleName, before
+// operation drops the role or revokes its privileges, which could lock the provider out of the cluster. Users the
+// server doesn't know, such as external (LDAP) users, can't be checked.
+func (c *asConnection) checkProviderRole(ctx context.Context, roleName, operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.userName == "" {
+		return diags
+	}
+
+	user, err := c.getClient(ctx).QueryUser(c.adminPolicy, c.userName)
+	if err != nil && err.Matches(astypes.INVALID_USER) {
+		return diags
+	}
+	if err != nil {
+		diags.AddError("Unable to read the roles of the provider user", err.Error())
+		return diags
+	}
+
+	if slices.Contains(user.Roles, roleName) {
+		diags.AddError("Can't change a role of the provider user",
+			"User "+c.userName+" is the user the provider connects with and holds role "+roleName+". "+
+				operation+" could lock the provider out of the cluster. Configure the provider with a different user first")
+	}
+
+	return diags
+}
+
+// checkWritable returns an error diagnostic if read_only is set, before operation changes anything.
+func (c *asConnection) checkWritable(operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.readOnly {
+		diags.AddError("Provider is read only",
+			operation+" is not allowed because read_only is set in the provider configuration. "+
+				"Plans, refreshes and data sources work, applies that change resources fail")
+	}
+
+	return diags
+}
+
+// startedDisconnected reports whether client was created although it couldn't connect to the cluster, which the
+// native client does when FailIfNotConnected is false. The client then keeps connecting in the background.
+func startedDisconnected(client as.ClientIfc) bool {
+	c, ok := client.(*as.Client)
+	return ok && c != nil && c.Cluster() != nil
+}
+
+// waitForNodes waits until the client sees at least expected nodes, or fails at the deadline.
+func waitForNodes(ctx context.Context, client aerospikeClient, expected int, deadline time.Time) error {
+	for {
+		nodes := len(client.GetNodes())
+		if nodes >= expected {
+			return nil
+		}
+		if time.Now().Add(clusterStablePollInterval).After(deadline) {
+			return fmt.Errorf("the cluster has %d of the %d expected nodes", nodes, expected)
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("waiting for the cluster to form, %d of %d nodes", nodes, expected))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(clusterStablePollInterval):
+		}
+	}
+}
+
+// peersRegexp matches a peer in a peers-clear-std or peers-tls-std response, e.g. [BB9020011AC4202,,[172.17.0.2]].
+// IPv6 addresses are in brackets, e.g. [BB9020011AC4202,,[[::1]:3100]].
+var peersRegexp = regexp.MustCompile(`\[([0-9A-Fa-f]+),[^,\[\]]*,\[((?:[^\[\]]|\[[^\]]*\])*)\]\]`)
+
+// parsePeers returns the addresses of the peers in a peers-clear-std or peers-tls-std response, keyed by node name.
+func parsePeers(response string) map[string]string {
+	peers := map[string]string{}
+	for _, m := range peersRegexp.FindAllStringSubmatch(response, -1) {
+		peers[m[1]] = m[2]
+	}
+
+	return peers
+}
+
+// unreachablePeers returns the peers the client isn't connected to, formatted as "node (addresses)".
+func unreachablePeers(peers map[string]string, connected []string) []string {
+	var unreachable []string
+	for _, name := range sortedKeys(peers) {
+		if !slices.Contains(connected, name) {
+			unreachable = append(unreachable, fmt.Sprintf("%s (%s)", name, peers[name]))
+		}
+	}
+
+	return unreachable
+}
+
+// checkConnectedNodes warns about the cluster nodes the client isn't connected to, as reported by the nodes it is
+// connected to, and fails if it is connected to fewer than minNodes nodes. peersCommand is peers-tls-std with TLS and
+// peers-clear-std without.
+func (c *asConnection) checkConnectedNodes(ctx context.Context, peersCommand string, minNodes int) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var connected []string
+	for _, n := range c.getClient(ctx).GetNodes() {
+		connected = append(connected, n.GetName())
+	}
+
+	var unreachable []string
+	if len(connected) == 0 {
+		diags.AddWarning("Not connected to the cluster",
+			"The provider isn't connected to any cluster node, commands fail until the client connects")
+	} else {
+		responses, err := c.infoAllNodes(ctx, peersCommand)
+		if err != nil {
+			diags.AddWarning("Unable to list the cluster nodes", "Unreachable nodes can't be reported: "+err.Error())
+		} else {
+			peers := map[string]string{}
+			for _, response := range responses {
+				maps.Copy(peers, parsePeers(response))
+			}
+			unreachable = unreachablePeers(peers, connected)
+			if len(unreachable) > 0 {
+				diags.AddWarning("Some cluster nodes are unreachable",
+					fmt.Sprintf("The provider is connected to %d nodes, these nodes are in the cluster but unreachable: %s",
+						len(connected), strings.Join(unreachable, ", ")))
+			}
+		}
+	}
+
+	if len(connected) < minNodes {
+		diags.AddAttributeError(path.Root("min_connected_nodes"), "Not enough cluster nodes",
+			fmt.Sprintf("The provider is connected to %d nodes, min_connected_nodes is %d", len(connected), minNodes))
+	}
+
+	return diags
+}
+
+// AerospikeConnectionModel describes the connection block of resources that can manage another cluster than the
+// provider's.
+type AerospikeConnectionModel struct {
+	Host      types.String `tfsdk:"host"`
+	Port      types.Int64  `tfsdk:"port"`
+	User_name types.String `tfsdk:"user_name"`
+	Password  types.String `tfsdk:"password"`
+}
+
+// overrideKey identifies the connection of a connection block. The password is hashed so a rotated password gets a
+// new connection without keeping the password in the key.
+func overrideKey(host *as.Host, user, password string) string {
+	return fmt.Sprintf("%s:%d:%s:%s:%x", host.Name, host.Port, host.TLSName, user, sha256.Sum256([]byte(password)))
+}
+
+// connectionAttribute is the schema of the connection block.
+func connectionAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Manage the resource on another cluster than the provider's, e.g. an XDR destination. " +
+			"The connection uses the provider's TLS and client settings. Changing it re-creates the resource",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Description: "Seed host to connect to. May include the port, e.g. db1:3000 or [::1]:3000",
+				Required:    true,
+			},
+			"port": schema.Int64Attribute{
+				Description: "Port to connect to. Defaults to the provider port",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+			},
+			"user_name": schema.StringAttribute{
+				Description: "Admin username. Defaults to the provider user",
+				Optional:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Admin password. Defaults to the provider password",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// overrideConnection returns the connection for a resource's connection block, or c when the block isn't set.
+// Connections are created on first use and shared by all the resources with the same host, port, user and password.
+// The TLS settings are the provider's, so they are the same for all of them.
+// Like the Configure of the security resources, it fails if the cluster can't manage users and roles.
+func (c *asConnection) overrideConnection(ctx context.Context, override types.Object, typeName string) (*asConnection, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var data AerospikeConnectionModel
+
+	if override.IsNull() || override.IsUnknown() {
+		return c, diags
+	}
+
+	diags.Append(override.As(ctx, &data, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return c, diags
+	}
+
+	cp := *c.clientPolicy
+	if !data.User_name.IsNull() {
+		cp.User = data.User_name.ValueString()
+	}
+	if !data.Password.IsNull() {
+		cp.Password = data.Password.ValueString()
+	}
+	hostName, port, err := splitHost(data.Host.ValueString(), data.Port.ValueInt64())
+	if err != nil {
+		diags.AddAttributeError(path.Root("connection").AtName("host"), "Invalid host", err.Error())
+		return c, diags
+	}
+	if port == 0 {
+		port = int64(c.hosts[0].Port)
+	}
+	host := as.NewHost(hostName, int(port))
+	host.TLSName = c.hosts[0].TLSName
+	key := overrideKey(host, cp.User, cp.Password)
+
+	c.overridesMutex.Lock()
+	defer c.overridesMutex.Unlock()
+
+	conn, ok := c.overrides[key]
+	if ok {
+		if err := conn.ensureConnected(ctx); err != nil {
+			diags.AddAttributeError(path.Root("connection"), "Unable to reconnect to Aerospike", err.Error())
+			return c, diags
+		}
+		return conn, diags
+	}
+
+	client, err := as.CreateClientWithPolicyAndHost(c.clientType, &cp, host)
+	if err != nil {
+		diags.AddAttributeError(path.Root("connection"), "Error connecting to Aerospike",
+			"Error connecting to Aerospike cluster "+host.Name+" "+err.Error())
+		return c, diags
+	}
+
+	conn = &asConnection{
+		client:             client,
+		clientType:         c.clientType,
+		clientPolicy:       &cp,
+		hosts:              []*as.Host{host},
+		adminPolicy:        c.adminPolicy,
+		infoPolicy:         c.infoPolicy,
+		readPolicy:         c.readPolicy,
+		userName:           cp.User,
+		debugInfoResponses: c.debugInfoResponses,
+		blockDestructive:   c.blockDestructive,
+		readOnly:           c.readOnly,
+		auditLog:           c.auditLog,
+		retryPolicy:        c.retryPolicy,
+		infoTarget:         c.infoTarget,
+		enterprise:         true,
+		securityEnabled:    true,
+	}
+	if conn.supportsInfo() {
+		if edErr := conn.detectEdition(ctx); edErr != nil {
+			diags.AddWarning("Unable to detect the server edition",
+				"Enterprise only resources will be used without checking the edition of "+host.Name+": "+edErr.Error())
+		}
+	}
+	diags.Append(conn.requireSecurity(typeName)...)
+	if diags.HasError() {
+		client.Close()
+		return c, diags
+	}
+
+	if c.overrides == nil {
+		c.overrides = make(map[string]*asConnection)
+	}
+	c.overrides[key] = conn
+
+	return conn, diags
+}
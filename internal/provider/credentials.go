@@ -0,0 +1,82 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// readPasswordFile returns the content of a password file without trailing newlines. An empty password is an error.
+func readPasswordFile(fileName string) (string, error) {
+	fileName, err := expandHome(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	password := strings.TrimRight(string(content), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("%s is empty", fileName)
+	}
+
+	return password, nil
+}
+
+// readRootCAFile returns the certificates of a PEM CA bundle. The whole file is read, so bundles of any size can be
+// used.
+func readRootCAFile(fileName string) (*x509.CertPool, error) {
+	fileName, err := resolvePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("%s doesn't contain any PEM certificates", fileName)
+	}
+
+	return roots, nil
+}
+
+// runPasswordCommand runs command and returns its standard output without trailing newlines. A command that prints
+// nothing fails.
+func runPasswordCommand(ctx context.Context, command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	password := strings.TrimRight(stdout.String(), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("%s printed no password", args[0])
+	}
+
+	return password, nil
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "no newline", content: "secret", want: "secret"},
+		{name: "trailing newline", content: "secret\n", want: "secret"},
+		{name: "windows newlines", content: "secret\r\n\r\n", want: "secret"},
+		{name: "inner spaces kept", content: " sec ret\n", want: " sec ret"},
+		{name: "empty", content: "", wantErr: true},
+		{name: "only a newline", content: "\n", wantErr: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := filepath.Join(dir, fmt.Sprintf("password%d", i))
+			if err := os.WriteFile(fileName, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := readPasswordFile(fileName)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readPasswordFile() = %q, %v, want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := readPasswordFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("readPasswordFile() of a missing file returned no error")
+	}
+}
+
+func TestRunPasswordCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    string
+		wantErr bool
+	}{
+		{name: "trailing newline", command: "echo secret", want: "secret"},
+		{name: "no newline", command: "printf secret", want: "secret"},
+		{name: "non-zero exit", command: "false", wantErr: true},
+		{name: "empty output", command: "true", wantErr: true},
+		{name: "only a newline", command: "echo", wantErr: true},
+		{name: "empty command", command: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runPasswordCommand(context.Background(), tt.command)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("runPasswordCommand(%q) = %q, %v, want %q, error %v", tt.command, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// testCACertPEM returns a self-signed CA certificate in PEM format.
+func testCACertPEM(t *testing.T, serial int64) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestReadRootCAFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// a bundle larger than a single read of a buffered reader
+	var bundle []byte
+	for i := int64(1); len(bundle) < 16*1024; i++ {
+		bundle = append(bundle, testCACertPEM(t, i)...)
+	}
+	bundleFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(bundleFile, bundle, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRootCAFile(bundleFile); err != nil {
+		t.Errorf("readRootCAFile() = %v, want nil", err)
+	}
+
+	t.Setenv("HOME", dir)
+	if _, err := readRootCAFile("~/ca.pem"); err != nil {
+		t.Errorf("readRootCAFile() of a path in the home directory = %v, want nil", err)
+	}
+
+	// relative to the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	if _, err := readRootCAFile("ca.pem"); err != nil {
+		t.Errorf("readRootCAFile() of a relative path = %v, want nil", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "empty.pem"), []byte("not a certificate\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readRootCAFile("empty.pem"); err == nil {
+		t.Error("readRootCAFile() returned no error for a file without certificates")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeClusterStableDataSource{}
+
+func NewAerospikeClusterStableDataSource() datasource.DataSource {
+	return &AerospikeClusterStableDataSource{}
+}
+
+// AerospikeClusterStableDataSource defines the data source implementation.
+type AerospikeClusterStableDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeClusterStableDataSourceModel describes the data source data model.
+type AerospikeClusterStableDataSourceModel struct {
+	Expected_size     types.Int64  `tfsdk:"expected_size"`
+	Namespace         types.String `tfsdk:"namespace"`
+	Ignore_migrations types.Bool   `tfsdk:"ignore_migrations"`
+	Wait_timeout      types.Int64  `tfsdk:"wait_timeout"`
+	Stable            types.Bool   `tfsdk:"stable"`
+	Cluster_key       types.String `tfsdk:"cluster_key"`
+	Reason            types.String `tfsdk:"reason"`
+}
+
+func (d *AerospikeClusterStableDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_stable"
+}
+
+func (d *AerospikeClusterStableDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Checks that the cluster is stable with the cluster-stable info command, optionally waiting until it is. " +
+			"Useful to make resources depend on a cluster that finished forming and migrating",
+
+		Attributes: map[string]schema.Attribute{
+			"expected_size": schema.Int64Attribute{
+				Description: "Expected number of nodes in the cluster. Optional - if null the size isn't checked",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Only check migrations of this namespace. Optional - if null all namespaces are checked",
+				Optional:    true,
+			},
+			"ignore_migrations": schema.BoolAttribute{
+				Description: "Consider the cluster stable while migrations are still running",
+				Optional:    true,
+			},
+			"wait_timeout": schema.Int64Attribute{
+				Description: "Seconds to wait for the cluster to become stable. The read fails if it isn't stable in time. " +
+					"Optional - if null the data source only reports the current state",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 3600),
+				},
+			},
+			"stable": schema.BoolAttribute{
+				Description: "Whether all the nodes agree on the cluster key and the checks passed",
+				Computed:    true,
+			},
+			"cluster_key": schema.StringAttribute{
+				Description: "Cluster key reported by the nodes when the cluster is stable",
+				Computed:    true,
+			},
+			"reason": schema.StringAttribute{
+				Description: "Why the cluster isn't stable, empty when it is",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *AerospikeClusterStableDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_cluster_stable is not supported",
+			"aerospike_cluster_stable uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeClusterStableDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeClusterStableDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command := clusterStableCommand(data.Expected_size.ValueInt64(), data.Namespace.ValueString(), data.Ignore_migrations.ValueBool())
+	deadline := time.Now().Add(time.Duration(data.Wait_timeout.ValueInt64()) * time.Second)
+
+	for {
+		var key, reason string
+		responses, err := d.asConn.infoAllNodes(ctx, command)
+		switch {
+		case err == nil:
+			key, reason = clusterStableKey(responses)
+		case data.Wait_timeout.IsNull():
+			resp.Diagnostics.AddError("Error checking cluster stability", err.Error())
+			return
+		default:
+			// nodes may still be joining, keep waiting
+			reason = err.Error()
+		}
+		if reason == "" || data.Wait_timeout.IsNull() {
+			data.Stable = types.BoolValue(reason == "")
+			data.Cluster_key = types.StringValue(key)
+			data.Reason = types.StringValue(reason)
+			break
+		}
+
+		if time.Now().Add(clusterStablePollInterval).After(deadline) {
+			resp.Diagnostics.AddError("Cluster not stable",
+				fmt.Sprintf("The cluster did not become stable within %d seconds: %s", data.Wait_timeout.ValueInt64(), reason))
+			return
+		}
+
+		tflog.Info(ctx, "waiting for the cluster to become stable: "+reason)
+
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError("Cluster not stable", "Canceled while waiting for the cluster to become stable: "+reason)
+			return
+		case <-time.After(clusterStablePollInterval):
+		}
+	}
+
+	tflog.Trace(ctx, "checked cluster stability with "+command)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
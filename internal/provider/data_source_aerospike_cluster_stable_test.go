@@ -0,0 +1,69 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeClusterStableDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "aerospike_cluster_stable" "test" {
+  wait_timeout = 30
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_cluster_stable.test", "stable", "true"),
+					resource.TestCheckResourceAttr("data.aerospike_cluster_stable.test", "reason", ""),
+					resource.TestCheckResourceAttrSet("data.aerospike_cluster_stable.test", "cluster_key"),
+				),
+			},
+		},
+	})
+}
+
+func TestClusterStableCommand(t *testing.T) {
+	cases := []struct {
+		size             int64
+		namespace        string
+		ignoreMigrations bool
+		want             string
+	}{
+		{0, "", false, "cluster-stable:ignore-migrations=false"},
+		{3, "", false, "cluster-stable:ignore-migrations=false;size=3"},
+		{3, "test", true, "cluster-stable:ignore-migrations=true;size=3;namespace=test"},
+	}
+
+	for _, c := range cases {
+		if got := clusterStableCommand(c.size, c.namespace, c.ignoreMigrations); got != c.want {
+			t.Errorf("clusterStableCommand(%d, %q, %v) = %q, want %q", c.size, c.namespace, c.ignoreMigrations, got, c.want)
+		}
+	}
+}
+
+func TestClusterStableKey(t *testing.T) {
+	cases := []struct {
+		name       string
+		responses  map[string]string
+		wantKey    string
+		wantStable bool
+	}{
+		{"stable", map[string]string{"A": "ED5ABE9E4E0A\n", "B": "ED5ABE9E4E0A"}, "ED5ABE9E4E0A", true},
+		{"different keys", map[string]string{"A": "ED5ABE9E4E0A", "B": "1A2B3C4D5E6F"}, "", false},
+		{"migrating", map[string]string{"A": "ED5ABE9E4E0A", "B": "ERROR::unstable-cluster"}, "", false},
+		{"no nodes", map[string]string{}, "", false},
+	}
+
+	for _, c := range cases {
+		key, reason := clusterStableKey(c.responses)
+		if key != c.wantKey || (reason == "") != c.wantStable {
+			t.Errorf("%s: clusterStableKey() = %q, %q, want key %q stable %v", c.name, key, reason, c.wantKey, c.wantStable)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeConfigComplianceDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &AerospikeConfigComplianceDataSource{}
+
+func NewAerospikeConfigComplianceDataSource() datasource.DataSource {
+	return &AerospikeConfigComplianceDataSource{}
+}
+
+// AerospikeConfigComplianceDataSource defines the data source implementation.
+type AerospikeConfigComplianceDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeConfigComplianceDataSourceModel describes the data source data model.
+type AerospikeConfigComplianceDataSourceModel struct {
+	Context     types.String                     `tfsdk:"context"`
+	Namespace   types.String                     `tfsdk:"namespace"`
+	Desired     map[string]types.String          `tfsdk:"desired"`
+	Compliant   types.Bool                       `tfsdk:"compliant"`
+	Differences []AerospikeConfigDifferenceModel `tfsdk:"differences"`
+}
+
+type AerospikeConfigDifferenceModel struct {
+	Node      types.String `tfsdk:"node"`
+	Parameter types.String `tfsdk:"parameter"`
+	Desired   types.String `tfsdk:"desired"`
+	Actual    types.String `tfsdk:"actual"`
+}
+
+// configDifference is a parameter whose value on a node differs from the desired one. actual is nil when the node
+// doesn't report the parameter.
+type configDifference struct {
+	node      string
+	parameter string
+	desired   string
+	actual    *string
+}
+
+func (d *AerospikeConfigComplianceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_compliance"
+}
+
+func (d *AerospikeConfigComplianceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Compares desired configuration parameters with the live values on every node, without changing anything. " +
+			"Useful to flag drift in scheduled plans on clusters that are partially managed outside terraform",
+
+		Attributes: map[string]schema.Attribute{
+			"context": schema.StringAttribute{
+				Description: "Configuration context. One of service or namespace",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("service", "namespace"),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace. Required for the namespace context",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"desired": schema.MapAttribute{
+				Description: "Map of configuration parameter names to their desired values, as they are returned by get-config",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"compliant": schema.BoolAttribute{
+				Description: "Whether every node has the desired values",
+				Computed:    true,
+			},
+			"differences": schema.ListNestedAttribute{
+				Description: "Parameters whose live value differs from the desired one, sorted by node and parameter",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Description: "Node",
+							Computed:    true,
+						},
+						"parameter": schema.StringAttribute{
+							Description: "Parameter name",
+							Computed:    true,
+						},
+						"desired": schema.StringAttribute{
+							Description: "Desired value",
+							Computed:    true,
+						},
+						"actual": schema.StringAttribute{
+							Description: "Value reported by the node, null when the node doesn't report the parameter",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AerospikeConfigComplianceDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data AerospikeConfigComplianceDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("context"), &data.Context)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &data.Namespace)...)
+
+	if resp.Diagnostics.HasError() || data.Context.IsUnknown() {
+		return
+	}
+
+	if data.Context.ValueString() == "namespace" && data.Namespace.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("namespace"), "Missing namespace",
+			"namespace must be set when context is \"namespace\"")
+	}
+	if data.Context.ValueString() != "namespace" && !data.Namespace.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("namespace"), "Invalid namespace",
+			"namespace can only be set for the namespace context")
+	}
+}
+
+func (d *AerospikeConfigComplianceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_config_compliance is not supported",
+			"aerospike_config_compliance uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeConfigComplianceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeConfigComplianceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command := getConfigCommand(data.Context.ValueString(), data.Namespace.ValueString(), "")
+	responses, err := d.asConn.infoAllNodes(ctx, command)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading configuration", err.Error())
+		return
+	}
+	for _, node := range sortedKeys(responses) {
+		if msg, ok := infoErrorMessage(responses[node]); ok {
+			resp.Diagnostics.AddError("Error reading configuration",
+				fmt.Sprintf("%s returned %s on node %s. Check that the namespace exists", command, msg, node))
+			return
+		}
+	}
+
+	desired := make(map[string]string, len(data.Desired))
+	for k, v := range data.Desired {
+		desired[k] = v.ValueString()
+	}
+
+	differences := configDifferences(desired, responses)
+	data.Compliant = types.BoolValue(len(differences) == 0)
+	data.Differences = make([]AerospikeConfigDifferenceModel, 0, len(differences))
+	for _, diff := range differences {
+		data.Differences = append(data.Differences, AerospikeConfigDifferenceModel{
+			Node:      types.StringValue(diff.node),
+			Parameter: types.StringValue(diff.parameter),
+			Desired:   types.StringValue(redactInfoValue(diff.parameter, diff.desired)),
+			Actual:    types.StringPointerValue(redactInfoPointer(diff.parameter, diff.actual)),
+		})
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("found %d configuration differences with %s", len(differences), command))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// configDifferences compares the desired parameters with the get-config responses of every node.
+func configDifferences(desired map[string]string, responses map[string]string) []configDifference {
+	var differences []configDifference
+
+	for _, node := range sortedKeys(responses) {
+		current := parseInfoParams(responses[node])
+		for _, k := range sortedKeys(desired) {
+			v, ok := current[k]
+			switch {
+			case !ok:
+				differences = append(differences, configDifference{node: node, parameter: k, desired: desired[k]})
+			case v != desired[k]:
+				differences = append(differences, configDifference{node: node, parameter: k, desired: desired[k], actual: &v})
+			}
+		}
+	}
+
+	return differences
+}
+
+// redactInfoPointer is redactInfoValue for optional values.
+func redactInfoPointer(param string, value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	redacted := redactInfoValue(param, *value)
+	return &redacted
+}
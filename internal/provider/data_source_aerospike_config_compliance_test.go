@@ -0,0 +1,54 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeConfigComplianceDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "aerospike_config_compliance" "test" {
+  context   = "namespace"
+  namespace = "aerospike"
+  desired = {
+    "no-such-parameter" = "1"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_config_compliance.test", "compliant", "false"),
+					resource.TestCheckResourceAttr("data.aerospike_config_compliance.test", "differences.0.parameter", "no-such-parameter"),
+					resource.TestCheckNoResourceAttr("data.aerospike_config_compliance.test", "differences.0.actual"),
+				),
+			},
+		},
+	})
+}
+
+func TestConfigDifferences(t *testing.T) {
+	responses := map[string]string{
+		"BB9020011AC4202": "migrate-threads=1;proto-fd-max=15000",
+		"BB9030011AC4202": "migrate-threads=2;proto-fd-max=15000",
+	}
+	got := configDifferences(map[string]string{"migrate-threads": "1", "proto-fd-max": "15000", "missing": "x"}, responses)
+
+	two := "2"
+	want := []configDifference{
+		{node: "BB9020011AC4202", parameter: "missing", desired: "x"},
+		{node: "BB9030011AC4202", parameter: "migrate-threads", desired: "1", actual: &two},
+		{node: "BB9030011AC4202", parameter: "missing", desired: "x"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configDifferences() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeEditionDataSource{}
+
+func NewAerospikeEditionDataSource() datasource.DataSource {
+	return &AerospikeEditionDataSource{}
+}
+
+// AerospikeEditionDataSource defines the data source implementation.
+type AerospikeEditionDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeEditionDataSourceModel describes the data source data model.
+type AerospikeEditionDataSourceModel struct {
+	Edition                       types.String   `tfsdk:"edition"`
+	Version                       types.String   `tfsdk:"version"`
+	Security_enabled              types.Bool     `tfsdk:"security_enabled"`
+	Quotas_enabled                types.Bool     `tfsdk:"quotas_enabled"`
+	Xdr_enabled                   types.Bool     `tfsdk:"xdr_enabled"`
+	Strong_consistency_licensed   types.Bool     `tfsdk:"strong_consistency_licensed"`
+	Strong_consistency_namespaces []types.String `tfsdk:"strong_consistency_namespaces"`
+}
+
+func (d *AerospikeEditionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_edition"
+}
+
+func (d *AerospikeEditionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Edition, version and enabled features of the cluster. Useful for preconditions, e.g. failing the plan when security is disabled",
+
+		Attributes: map[string]schema.Attribute{
+			"edition": schema.StringAttribute{
+				Description: "Server edition, enterprise or community",
+				Computed:    true,
+			},
+			"version": schema.StringAttribute{
+				Description: "Server version",
+				Computed:    true,
+			},
+			"security_enabled": schema.BoolAttribute{
+				Description: "Whether security (users and roles) is enabled",
+				Computed:    true,
+			},
+			"quotas_enabled": schema.BoolAttribute{
+				Description: "Whether role quotas are enabled",
+				Computed:    true,
+			},
+			"xdr_enabled": schema.BoolAttribute{
+				Description: "Whether XDR has at least one datacenter configured",
+				Computed:    true,
+			},
+			"strong_consistency_licensed": schema.BoolAttribute{
+				Description: "Whether the feature key allows strong consistency",
+				Computed:    true,
+			},
+			"strong_consistency_namespaces": schema.ListAttribute{
+				Description: "Sorted list of the namespaces with strong consistency enabled",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *AerospikeEditionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeEditionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeEditionDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	features, err := d.asConn.readClusterFeatures(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading cluster features", err.Error())
+		return
+	}
+
+	data.Edition = types.StringValue(features.edition)
+	data.Version = types.StringValue(features.version)
+	data.Security_enabled = types.BoolValue(features.securityEnabled)
+	data.Quotas_enabled = types.BoolValue(features.quotasEnabled)
+	data.Xdr_enabled = types.BoolValue(features.xdrEnabled)
+	data.Strong_consistency_licensed = types.BoolValue(features.strongConsistencyLicensed)
+	data.Strong_consistency_namespaces = make([]types.String, 0, len(features.strongConsistencyNamespaces))
+	for _, ns := range features.strongConsistencyNamespaces {
+		data.Strong_consistency_namespaces = append(data.Strong_consistency_namespaces, types.StringValue(ns))
+	}
+
+	tflog.Trace(ctx, "read cluster features, edition "+features.edition+" version "+features.version)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
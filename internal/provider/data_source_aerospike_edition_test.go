@@ -0,0 +1,27 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeEditionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "aerospike_edition" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_edition.test", "edition", "enterprise"),
+					resource.TestCheckResourceAttr("data.aerospike_edition.test", "security_enabled", "true"),
+					resource.TestCheckResourceAttrSet("data.aerospike_edition.test", "version"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,223 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeQuotaUsageDataSource{}
+
+func NewAerospikeQuotaUsageDataSource() datasource.DataSource {
+	return &AerospikeQuotaUsageDataSource{}
+}
+
+// AerospikeQuotaUsageDataSource defines the data source implementation.
+type AerospikeQuotaUsageDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeQuotaUsageDataSourceModel describes the data source data model.
+type AerospikeQuotaUsageDataSourceModel struct {
+	Name_prefix types.String                   `tfsdk:"name_prefix"`
+	Name_regex  types.String                   `tfsdk:"name_regex"`
+	Users       []AerospikeUserQuotaUsageModel `tfsdk:"users"`
+}
+
+type AerospikeUserQuotaUsageModel struct {
+	User_name       types.String   `tfsdk:"user_name"`
+	Roles           []types.String `tfsdk:"roles"`
+	Read_quota      types.Int64    `tfsdk:"read_quota"`
+	Read_tps        types.Int64    `tfsdk:"read_tps"`
+	Read_rps        types.Int64    `tfsdk:"read_rps"`
+	Read_limitless  types.Int64    `tfsdk:"read_limitless"`
+	Write_quota     types.Int64    `tfsdk:"write_quota"`
+	Write_tps       types.Int64    `tfsdk:"write_tps"`
+	Write_rps       types.Int64    `tfsdk:"write_rps"`
+	Write_limitless types.Int64    `tfsdk:"write_limitless"`
+	Connections     types.Int64    `tfsdk:"connections"`
+}
+
+func (d *AerospikeQuotaUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_quota_usage"
+}
+
+func (d *AerospikeQuotaUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Current quota usage of the users in the cluster. The quotas are the effective quotas the users get from their roles. " +
+			"Usage is only tracked by the server when quotas are enabled",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Description: "Only return users whose name starts with this prefix",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return users whose name matches this regular expression",
+				Optional:    true,
+			},
+			"users": schema.ListNestedAttribute{
+				Description: "Quota usage of the matching users, sorted by user name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"user_name": schema.StringAttribute{
+							Description: "User name",
+							Computed:    true,
+						},
+						"roles": schema.ListAttribute{
+							Description: "Roles granted to the user",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"read_quota": schema.Int64Attribute{
+							Description: "Read quota in records per second, 0 for no quota",
+							Computed:    true,
+						},
+						"read_tps": schema.Int64Attribute{
+							Description: "Single record read transactions per second",
+							Computed:    true,
+						},
+						"read_rps": schema.Int64Attribute{
+							Description: "Records per second read by scans and queries",
+							Computed:    true,
+						},
+						"read_limitless": schema.Int64Attribute{
+							Description: "Number of running read scans and queries that are not limited by the quota",
+							Computed:    true,
+						},
+						"write_quota": schema.Int64Attribute{
+							Description: "Write quota in records per second, 0 for no quota",
+							Computed:    true,
+						},
+						"write_tps": schema.Int64Attribute{
+							Description: "Single record write transactions per second",
+							Computed:    true,
+						},
+						"write_rps": schema.Int64Attribute{
+							Description: "Records per second written by background scans and queries",
+							Computed:    true,
+						},
+						"write_limitless": schema.Int64Attribute{
+							Description: "Number of running write scans and queries that are not limited by the quota",
+							Computed:    true,
+						},
+						"connections": schema.Int64Attribute{
+							Description: "Number of open connections of the user",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AerospikeQuotaUsageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_quota_usage")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeQuotaUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeQuotaUsageDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matches, err := nameMatcher(data.Name_prefix.ValueString(), data.Name_regex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+		return
+	}
+
+	users, asErr := d.asConn.getClient(ctx).QueryUsers(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying users", asErr.Error())
+		return
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].User < users[j].User })
+
+	data.Users = make([]AerospikeUserQuotaUsageModel, 0, len(users))
+	for _, u := range users {
+		if !matches(u.User) {
+			continue
+		}
+
+		usage := AerospikeUserQuotaUsageModel{
+			User_name:       types.StringValue(u.User),
+			Roles:           make([]types.String, 0, len(u.Roles)),
+			Read_quota:      types.Int64Value(statAt(u.ReadInfo, 0)),
+			Read_tps:        types.Int64Value(statAt(u.ReadInfo, 1)),
+			Read_rps:        types.Int64Value(statAt(u.ReadInfo, 2)),
+			Read_limitless:  types.Int64Value(statAt(u.ReadInfo, 3)),
+			Write_quota:     types.Int64Value(statAt(u.WriteInfo, 0)),
+			Write_tps:       types.Int64Value(statAt(u.WriteInfo, 1)),
+			Write_rps:       types.Int64Value(statAt(u.WriteInfo, 2)),
+			Write_limitless: types.Int64Value(statAt(u.WriteInfo, 3)),
+			Connections:     types.Int64Value(int64(u.ConnsInUse)),
+		}
+		for _, r := range u.Roles {
+			// Aerospike returns a one item array with "" for no roles
+			if r != "" {
+				usage.Roles = append(usage.Roles, types.StringValue(r))
+			}
+		}
+
+		data.Users = append(data.Users, usage)
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read quota usage of %d users", len(data.Users)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// statAt returns the user statistic at the given offset, or 0 if the server didn't report it.
+func statAt(stats []int, offset int) int64 {
+	if offset >= len(stats) {
+		return 0
+	}
+
+	return int64(stats[offset])
+}
@@ -0,0 +1,38 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeQuotaUsageDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_user" "testdsquotauser1" {
+  user_name = "testdsquotauser1"
+  password  = "testpass1"
+  roles     = ["read"]
+}
+
+data "aerospike_quota_usage" "test" {
+  name_prefix = "testdsquota"
+  depends_on  = [aerospike_user.testdsquotauser1]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_quota_usage.test", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.aerospike_quota_usage.test", "users.0.user_name", "testdsquotauser1"),
+					resource.TestCheckResourceAttr("data.aerospike_quota_usage.test", "users.0.roles.0", "read"),
+					resource.TestCheckResourceAttr("data.aerospike_quota_usage.test", "users.0.connections", "0"),
+				),
+			},
+		},
+	})
+}
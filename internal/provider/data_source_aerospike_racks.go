@@ -0,0 +1,205 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeRacksDataSource{}
+
+func NewAerospikeRacksDataSource() datasource.DataSource {
+	return &AerospikeRacksDataSource{}
+}
+
+// AerospikeRacksDataSource defines the data source implementation.
+type AerospikeRacksDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeRacksDataSourceModel describes the data source data model.
+type AerospikeRacksDataSourceModel struct {
+	Namespace types.String         `tfsdk:"namespace"`
+	Racks     []AerospikeRackModel `tfsdk:"racks"`
+}
+
+type AerospikeRackModel struct {
+	Namespace types.String   `tfsdk:"namespace"`
+	Rack_id   types.Int64    `tfsdk:"rack_id"`
+	Nodes     []types.String `tfsdk:"nodes"`
+}
+
+// rackInfo is a rack of a namespace as reported by the racks info command.
+type rackInfo struct {
+	namespace string
+	rackID    int64
+	nodes     []string
+}
+
+func (d *AerospikeRacksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_racks"
+}
+
+func (d *AerospikeRacksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Racks of every namespace and the nodes in them, as reported by the racks info command. " +
+			"Useful to check that a namespace spans more than one rack",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Only return the racks of this namespace",
+				Optional:    true,
+			},
+			"racks": schema.ListNestedAttribute{
+				Description: "Racks sorted by namespace and rack id",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"namespace": schema.StringAttribute{
+							Description: "Namespace",
+							Computed:    true,
+						},
+						"rack_id": schema.Int64Attribute{
+							Description: "Rack id, 0 when rack awareness isn't configured",
+							Computed:    true,
+						},
+						"nodes": schema.ListAttribute{
+							Description: "Sorted list of the nodes in the rack",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AerospikeRacksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_racks is not supported",
+			"aerospike_racks uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeRacksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeRacksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	res, err := d.asConn.infoAnyNode(ctx, "racks")
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading racks", err.Error())
+		return
+	}
+	if isInfoError(res) {
+		resp.Diagnostics.AddError("Error reading racks", "racks returned "+res)
+		return
+	}
+
+	data.Racks = make([]AerospikeRackModel, 0)
+	for _, rack := range parseRacks(res) {
+		if !data.Namespace.IsNull() && rack.namespace != data.Namespace.ValueString() {
+			continue
+		}
+
+		rackModel := AerospikeRackModel{
+			Namespace: types.StringValue(rack.namespace),
+			Rack_id:   types.Int64Value(rack.rackID),
+			Nodes:     make([]types.String, 0, len(rack.nodes)),
+		}
+		for _, n := range rack.nodes {
+			rackModel.Nodes = append(rackModel.Nodes, types.StringValue(n))
+		}
+		data.Racks = append(data.Racks, rackModel)
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d racks", len(data.Racks)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseRacks parses a racks response of the form "ns=test:rack_1=node1,node2:rack_2=node3;ns=bar:rack_0=node1".
+// The roster_rack_ entries of strong consistency namespaces describe the roster, not the current racks, and are
+// skipped.
+func parseRacks(response string) []rackInfo {
+	var racks []rackInfo
+
+	for _, entry := range strings.Split(strings.TrimSpace(response), ";") {
+		fields := strings.Split(entry, ":")
+		namespace, ok := strings.CutPrefix(fields[0], "ns=")
+		if !ok {
+			continue
+		}
+		for _, field := range fields[1:] {
+			k, v, _ := strings.Cut(field, "=")
+			id, ok := strings.CutPrefix(k, "rack_")
+			if !ok {
+				continue
+			}
+			rackID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			rack := rackInfo{namespace: namespace, rackID: rackID, nodes: make([]string, 0)}
+			for _, n := range strings.Split(v, ",") {
+				if n != "" {
+					rack.nodes = append(rack.nodes, n)
+				}
+			}
+			sort.Strings(rack.nodes)
+			racks = append(racks, rack)
+		}
+	}
+
+	sort.Slice(racks, func(i, j int) bool {
+		if racks[i].namespace != racks[j].namespace {
+			return racks[i].namespace < racks[j].namespace
+		}
+		return racks[i].rackID < racks[j].rackID
+	})
+
+	return racks
+}
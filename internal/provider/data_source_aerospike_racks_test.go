@@ -0,0 +1,42 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeRacksDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "aerospike_racks" "test" {
+  namespace = "aerospike"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_racks.test", "racks.0.namespace", "aerospike"),
+					resource.TestCheckResourceAttrSet("data.aerospike_racks.test", "racks.0.nodes.0"),
+				),
+			},
+		},
+	})
+}
+
+func TestParseRacks(t *testing.T) {
+	got := parseRacks("ns=test:roster_rack_1=B2,A1:rack_2=C3:rack_1=B2,A1;ns=bar:rack_0=A1,B2,C3\n")
+	want := []rackInfo{
+		{namespace: "bar", rackID: 0, nodes: []string{"A1", "B2", "C3"}},
+		{namespace: "test", rackID: 1, nodes: []string{"A1", "B2"}},
+		{namespace: "test", rackID: 2, nodes: []string{"C3"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRacks() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeRolesDataSource{}
+
+func NewAerospikeRolesDataSource() datasource.DataSource {
+	return &AerospikeRolesDataSource{}
+}
+
+// AerospikeRolesDataSource defines the data source implementation.
+type AerospikeRolesDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeRolesDataSourceModel describes the data source data model.
+type AerospikeRolesDataSourceModel struct {
+	Name_prefix types.String   `tfsdk:"name_prefix"`
+	Name_regex  types.String   `tfsdk:"name_regex"`
+	Role_names  []types.String `tfsdk:"role_names"`
+}
+
+func (d *AerospikeRolesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_roles"
+}
+
+func (d *AerospikeRolesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Names of the roles defined in the cluster, excluding the predefined roles. Useful for generating import blocks for existing roles",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Description: "Only return roles whose name starts with this prefix",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return roles whose name matches this regular expression",
+				Optional:    true,
+			},
+			"role_names": schema.ListAttribute{
+				Description: "Sorted list of matching role names",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *AerospikeRolesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_roles")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeRolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeRolesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matches, err := nameMatcher(data.Name_prefix.ValueString(), data.Name_regex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+		return
+	}
+
+	roles, asErr := d.asConn.getClient(ctx).QueryRoles(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying roles", asErr.Error())
+		return
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, r := range roles {
+		// predefined roles can't be managed, skip them
+		if matches(r.Name) && !sliceutil.Contains(privilegeNames, r.Name) {
+			names = append(names, r.Name)
+		}
+	}
+	sort.Strings(names)
+
+	data.Role_names = make([]types.String, 0, len(names))
+	for _, n := range names {
+		data.Role_names = append(data.Role_names, types.StringValue(n))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d matching roles", len(names)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
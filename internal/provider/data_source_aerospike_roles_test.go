@@ -0,0 +1,35 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeRolesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_role" "testdsrole1" {
+  role_name  = "testdsrole1"
+  privileges = [{ privilege = "read" }]
+}
+
+data "aerospike_roles" "test" {
+  name_regex = "^testds"
+  depends_on = [aerospike_role.testdsrole1]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_roles.test", "role_names.#", "1"),
+					resource.TestCheckResourceAttr("data.aerospike_roles.test", "role_names.0", "testdsrole1"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeSecurityExportDataSource{}
+
+func NewAerospikeSecurityExportDataSource() datasource.DataSource {
+	return &AerospikeSecurityExportDataSource{}
+}
+
+// AerospikeSecurityExportDataSource defines the data source implementation.
+type AerospikeSecurityExportDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeSecurityExportDataSourceModel describes the data source data model.
+type AerospikeSecurityExportDataSourceModel struct {
+	Json types.String `tfsdk:"json"`
+}
+
+// securityExport is the exported users and roles. The json names match the attributes of aerospike_user and
+// aerospike_role so the export can be decoded and fed to for_each on another cluster.
+type securityExport struct {
+	Users []securityExportUser `json:"users"`
+	Roles []securityExportRole `json:"roles"`
+}
+
+type securityExportUser struct {
+	User_name string   `json:"user_name"`
+	Roles     []string `json:"roles"`
+}
+
+type securityExportRole struct {
+	Role_name   string                    `json:"role_name"`
+	Privileges  []securityExportPrivilege `json:"privileges"`
+	White_list  []string                  `json:"white_list"`
+	Read_quota  uint32                    `json:"read_quota"`
+	Write_quota uint32                    `json:"write_quota"`
+}
+
+type securityExportPrivilege struct {
+	Privilege string `json:"privilege"`
+	Namespace string `json:"namespace,omitempty"`
+	Set       string `json:"set,omitempty"`
+}
+
+func (d *AerospikeSecurityExportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_security_export"
+}
+
+func (d *AerospikeSecurityExportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Exports all users and roles of the cluster as a single JSON document, for backups, audit archives or " +
+			"bootstrapping a DR cluster. Passwords can't be read from the server and aren't exported",
+
+		Attributes: map[string]schema.Attribute{
+			"json": schema.StringAttribute{
+				Description: "JSON object with a users list of {user_name, roles} and a roles list of " +
+					"{role_name, privileges, white_list, read_quota, write_quota}, as they are named in aerospike_user and aerospike_role. " +
+					"Users, roles and their lists are sorted so the export only changes when the security configuration does. " +
+					"Predefined roles are excluded",
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *AerospikeSecurityExportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_security_export")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeSecurityExportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeSecurityExportDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	users, asErr := d.asConn.getClient(ctx).QueryUsers(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying users", asErr.Error())
+		return
+	}
+
+	roles, asErr := d.asConn.getClient(ctx).QueryRoles(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying roles", asErr.Error())
+		return
+	}
+
+	export, err := json.Marshal(buildSecurityExport(users, roles))
+	if err != nil {
+		resp.Diagnostics.AddError("Error encoding security export", err.Error())
+		return
+	}
+	data.Json = types.StringValue(string(export))
+
+	tflog.Trace(ctx, fmt.Sprintf("exported %d users and %d roles", len(users), len(roles)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// buildSecurityExport converts the users and roles of the cluster to their export format, sorted.
+func buildSecurityExport(users []*as.UserRoles, roles []*as.Role) securityExport {
+	export := securityExport{
+		Users: make([]securityExportUser, 0, len(users)),
+		Roles: make([]securityExportRole, 0, len(roles)),
+	}
+
+	for _, u := range users {
+		userRoles := append([]string{}, u.Roles...)
+		sort.Strings(userRoles)
+		export.Users = append(export.Users, securityExportUser{User_name: u.User, Roles: userRoles})
+	}
+	sort.Slice(export.Users, func(i, j int) bool { return export.Users[i].User_name < export.Users[j].User_name })
+
+	for _, r := range roles {
+		// predefined roles exist on every cluster and can't be changed, skip them
+		if sliceutil.Contains(privilegeNames, r.Name) {
+			continue
+		}
+
+		privileges := make([]securityExportPrivilege, 0, len(r.Privileges))
+		for _, p := range r.Privileges {
+			privileges = append(privileges, securityExportPrivilege{Privilege: string(p.Code), Namespace: p.Namespace, Set: p.SetName})
+		}
+		sort.Slice(privileges, func(i, j int) bool {
+			a, b := privileges[i], privileges[j]
+			if a.Privilege != b.Privilege {
+				return a.Privilege < b.Privilege
+			}
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			return a.Set < b.Set
+		})
+
+		whiteList := append([]string{}, r.Whitelist...)
+		sort.Strings(whiteList)
+
+		export.Roles = append(export.Roles, securityExportRole{
+			Role_name:   r.Name,
+			Privileges:  privileges,
+			White_list:  whiteList,
+			Read_quota:  r.ReadQuota,
+			Write_quota: r.WriteQuota,
+		})
+	}
+	sort.Slice(export.Roles, func(i, j int) bool { return export.Roles[i].Role_name < export.Roles[j].Role_name })
+
+	return export
+}
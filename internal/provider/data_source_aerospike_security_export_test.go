@@ -0,0 +1,79 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeSecurityExportDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_role" "testdsexportrole1" {
+  role_name  = "testdsexportrole1"
+  privileges = [{ privilege = "read", namespace = "test" }]
+  white_list = ["10.0.0.0/8"]
+}
+
+resource "aerospike_user" "testdsexportuser1" {
+  user_name = "testdsexportuser1"
+  password  = "testpass1"
+  roles     = [aerospike_role.testdsexportrole1.role_name]
+}
+
+data "aerospike_security_export" "test" {
+  depends_on = [aerospike_user.testdsexportuser1]
+}
+
+locals {
+  export = jsondecode(data.aerospike_security_export.test.json)
+}
+
+output "export_role" {
+  value = one([for r in local.export.roles : r if r.role_name == "testdsexportrole1"]).privileges[0].namespace
+}
+
+output "export_user" {
+  value = one([for u in local.export.users : u if u.user_name == "testdsexportuser1"]).roles[0]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("export_role", "test"),
+					resource.TestCheckOutput("export_user", "testdsexportrole1"),
+				),
+			},
+		},
+	})
+}
+
+func TestBuildSecurityExport(t *testing.T) {
+	users := []*as.UserRoles{
+		{User: "ops", Roles: []string{"sys-admin", "app"}},
+		{User: "admin", Roles: []string{"user-admin"}},
+	}
+	roles := []*as.Role{
+		{Name: "sys-admin", Privileges: []as.Privilege{{Code: as.SysAdmin}}},
+		{Name: "app", Privileges: []as.Privilege{{Code: as.Write, Namespace: "test", SetName: "s"}, {Code: as.Read, Namespace: "test"}},
+			Whitelist: []string{"10.0.0.2", "10.0.0.1"}, ReadQuota: 100},
+	}
+
+	got, err := json.Marshal(buildSecurityExport(users, roles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"users":[{"user_name":"admin","roles":["user-admin"]},{"user_name":"ops","roles":["app","sys-admin"]}],` +
+		`"roles":[{"role_name":"app","privileges":[{"privilege":"read","namespace":"test"},{"privilege":"write","namespace":"test","set":"s"}],` +
+		`"white_list":["10.0.0.1","10.0.0.2"],"read_quota":100,"write_quota":0}]}`
+
+	if string(got) != want {
+		t.Errorf("buildSecurityExport() = %s, want %s", got, want)
+	}
+}
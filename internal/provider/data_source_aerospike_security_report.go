@@ -0,0 +1,240 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeSecurityReportDataSource{}
+
+// adminPrivileges are the privileges that allow managing users, roles or the cluster configuration.
+var adminPrivileges = []string{"user-admin", "sys-admin", "data-admin"}
+
+func NewAerospikeSecurityReportDataSource() datasource.DataSource {
+	return &AerospikeSecurityReportDataSource{}
+}
+
+// AerospikeSecurityReportDataSource defines the data source implementation.
+type AerospikeSecurityReportDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeSecurityReportDataSourceModel describes the data source data model.
+type AerospikeSecurityReportDataSourceModel struct {
+	Admin_users             []AerospikeSecurityReportUserModel `tfsdk:"admin_users"`
+	Roles_without_whitelist []types.String                     `tfsdk:"roles_without_whitelist"`
+	Roles_without_quotas    []types.String                     `tfsdk:"roles_without_quotas"`
+	Inactive_users          []types.String                     `tfsdk:"inactive_users"`
+}
+
+type AerospikeSecurityReportUserModel struct {
+	User_name   types.String   `tfsdk:"user_name"`
+	Admin_roles []types.String `tfsdk:"admin_roles"`
+}
+
+// securityReport is the result of checking the users and roles of a cluster.
+type securityReport struct {
+	adminUsers            map[string][]string
+	rolesWithoutWhitelist []string
+	rolesWithoutQuotas    []string
+	inactiveUsers         []string
+}
+
+func (d *AerospikeSecurityReportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_security_report"
+}
+
+func (d *AerospikeSecurityReportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Security posture of the cluster: admin users, and roles without a whitelist or quotas. " +
+			"The lists can be used in check blocks or preconditions to gate applies on compliance rules",
+
+		Attributes: map[string]schema.Attribute{
+			"admin_users": schema.ListNestedAttribute{
+				Description: "Users granted a role with the user-admin, sys-admin or data-admin privilege, sorted by user name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"user_name": schema.StringAttribute{
+							Description: "User name",
+							Computed:    true,
+						},
+						"admin_roles": schema.ListAttribute{
+							Description: "Sorted list of the user's roles that grant an admin privilege",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"roles_without_whitelist": schema.ListAttribute{
+				Description: "Sorted list of the roles that can be used from any address. Predefined roles are excluded",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"roles_without_quotas": schema.ListAttribute{
+				Description: "Sorted list of the roles without a read or a write quota. Predefined roles are excluded",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"inactive_users": schema.ListAttribute{
+				Description: "Sorted list of the users without open connections or current reads and writes. " +
+					"The server doesn't report when a user last logged in, so this only reflects current activity",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *AerospikeSecurityReportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_security_report")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeSecurityReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeSecurityReportDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	users, asErr := d.asConn.getClient(ctx).QueryUsers(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying users", asErr.Error())
+		return
+	}
+
+	roles, asErr := d.asConn.getClient(ctx).QueryRoles(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying roles", asErr.Error())
+		return
+	}
+
+	report := buildSecurityReport(users, roles)
+
+	userNames := make([]string, 0, len(report.adminUsers))
+	for u := range report.adminUsers {
+		userNames = append(userNames, u)
+	}
+	sort.Strings(userNames)
+
+	data.Admin_users = make([]AerospikeSecurityReportUserModel, 0, len(userNames))
+	for _, u := range userNames {
+		data.Admin_users = append(data.Admin_users, AerospikeSecurityReportUserModel{
+			User_name:   types.StringValue(u),
+			Admin_roles: stringValues(report.adminUsers[u]),
+		})
+	}
+	data.Roles_without_whitelist = stringValues(report.rolesWithoutWhitelist)
+	data.Roles_without_quotas = stringValues(report.rolesWithoutQuotas)
+	data.Inactive_users = stringValues(report.inactiveUsers)
+
+	tflog.Trace(ctx, fmt.Sprintf("checked %d users and %d roles", len(users), len(roles)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// buildSecurityReport checks the users and roles of the cluster. All the returned lists are sorted.
+func buildSecurityReport(users []*as.UserRoles, roles []*as.Role) securityReport {
+	report := securityReport{
+		adminUsers:            make(map[string][]string),
+		rolesWithoutWhitelist: make([]string, 0),
+		rolesWithoutQuotas:    make([]string, 0),
+		inactiveUsers:         make([]string, 0),
+	}
+
+	// the predefined admin roles have the privilege of the same name
+	adminRoles := append([]string{}, adminPrivileges...)
+	for _, r := range roles {
+		// predefined roles can't have a whitelist or quotas, don't report them
+		if sliceutil.Contains(privilegeNames, r.Name) {
+			continue
+		}
+
+		for _, p := range r.Privileges {
+			if sliceutil.Contains(adminPrivileges, string(p.Code)) {
+				adminRoles = append(adminRoles, r.Name)
+				break
+			}
+		}
+		if len(r.Whitelist) == 0 {
+			report.rolesWithoutWhitelist = append(report.rolesWithoutWhitelist, r.Name)
+		}
+		if r.ReadQuota == 0 || r.WriteQuota == 0 {
+			report.rolesWithoutQuotas = append(report.rolesWithoutQuotas, r.Name)
+		}
+	}
+
+	for _, u := range users {
+		for _, r := range u.Roles {
+			if sliceutil.Contains(adminRoles, r) {
+				report.adminUsers[u.User] = append(report.adminUsers[u.User], r)
+			}
+		}
+		sort.Strings(report.adminUsers[u.User])
+
+		if u.ConnsInUse == 0 && statAt(u.ReadInfo, 1)+statAt(u.ReadInfo, 2) == 0 &&
+			statAt(u.WriteInfo, 1)+statAt(u.WriteInfo, 2) == 0 {
+			report.inactiveUsers = append(report.inactiveUsers, u.User)
+		}
+	}
+
+	sort.Strings(report.rolesWithoutWhitelist)
+	sort.Strings(report.rolesWithoutQuotas)
+	sort.Strings(report.inactiveUsers)
+
+	return report
+}
+
+// stringValues converts a list of strings to terraform values.
+func stringValues(values []string) []types.String {
+	res := make([]types.String, 0, len(values))
+	for _, v := range values {
+		res = append(res, types.StringValue(v))
+	}
+
+	return res
+}
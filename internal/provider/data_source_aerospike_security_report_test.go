@@ -0,0 +1,73 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeSecurityReportDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_role" "testdsreportrole1" {
+  role_name  = "testdsreportrole1"
+  privileges = [{ privilege = "sys-admin" }]
+}
+
+resource "aerospike_user" "testdsreportuser1" {
+  user_name = "testdsreportuser1"
+  password  = "testpass1"
+  roles     = [aerospike_role.testdsreportrole1.role_name]
+}
+
+data "aerospike_security_report" "test" {
+  depends_on = [aerospike_user.testdsreportuser1]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("data.aerospike_security_report.test", "admin_users.*", map[string]string{
+						"user_name":     "testdsreportuser1",
+						"admin_roles.0": "testdsreportrole1",
+					}),
+					resource.TestCheckTypeSetElemAttr("data.aerospike_security_report.test", "roles_without_whitelist.*", "testdsreportrole1"),
+					resource.TestCheckTypeSetElemAttr("data.aerospike_security_report.test", "roles_without_quotas.*", "testdsreportrole1"),
+					resource.TestCheckTypeSetElemAttr("data.aerospike_security_report.test", "inactive_users.*", "testdsreportuser1"),
+				),
+			},
+		},
+	})
+}
+
+func TestBuildSecurityReport(t *testing.T) {
+	users := []*as.UserRoles{
+		{User: "ops", Roles: []string{"read", "ops"}, ConnsInUse: 1},
+		{User: "admin", Roles: []string{"user-admin", "sys-admin"}},
+		{User: "app", Roles: []string{"app"}, ReadInfo: []int{0, 5, 0, 0}},
+	}
+	roles := []*as.Role{
+		{Name: "sys-admin", Privileges: []as.Privilege{{Code: as.SysAdmin}}},
+		{Name: "read", Privileges: []as.Privilege{{Code: as.Read}}},
+		{Name: "ops", Privileges: []as.Privilege{{Code: as.Read}, {Code: as.DataAdmin}}, Whitelist: []string{"10.0.0.0/8"}},
+		{Name: "app", Privileges: []as.Privilege{{Code: as.ReadWrite, Namespace: "test"}}, ReadQuota: 100, WriteQuota: 100},
+	}
+
+	got := buildSecurityReport(users, roles)
+	want := securityReport{
+		adminUsers:            map[string][]string{"admin": {"sys-admin", "user-admin"}, "ops": {"ops"}},
+		rolesWithoutWhitelist: []string{"app"},
+		rolesWithoutQuotas:    []string{"ops"},
+		inactiveUsers:         []string{"admin"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSecurityReport() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeSetDataSource{}
+
+func NewAerospikeSetDataSource() datasource.DataSource {
+	return &AerospikeSetDataSource{}
+}
+
+// AerospikeSetDataSource defines the data source implementation.
+type AerospikeSetDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeSetDataSourceModel describes the data source data model.
+type AerospikeSetDataSourceModel struct {
+	Namespace         types.String `tfsdk:"namespace"`
+	Set               types.String `tfsdk:"set"`
+	Objects           types.Int64  `tfsdk:"objects"`
+	Tombstones        types.Int64  `tfsdk:"tombstones"`
+	Data_used_bytes   types.Int64  `tfsdk:"data_used_bytes"`
+	Memory_data_bytes types.Int64  `tfsdk:"memory_data_bytes"`
+	Device_data_bytes types.Int64  `tfsdk:"device_data_bytes"`
+	Default_ttl       types.Int64  `tfsdk:"default_ttl"`
+	Stop_writes_count types.Int64  `tfsdk:"stop_writes_count"`
+	Stop_writes_size  types.Int64  `tfsdk:"stop_writes_size"`
+	Index_enabled     types.Bool   `tfsdk:"index_enabled"`
+	Index_populating  types.Bool   `tfsdk:"index_populating"`
+}
+
+// setStatistics are the set statistics summed over the nodes. The other values of the set are its configuration,
+// which is the same on every node.
+var setStatistics = []string{"objects", "tombstones", "data_used_bytes", "memory_data_bytes", "device_data_bytes"}
+
+func (d *AerospikeSetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_set"
+}
+
+func (d *AerospikeSetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Statistics and configuration of a set. Statistics are summed over the nodes, so they include the replicas. " +
+			"Useful for capacity checks",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Namespace",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"set": schema.StringAttribute{
+				Description: "Set",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 63),
+				},
+			},
+			"objects": schema.Int64Attribute{
+				Description: "Number of records in the set",
+				Computed:    true,
+			},
+			"tombstones": schema.Int64Attribute{
+				Description: "Number of tombstones in the set",
+				Computed:    true,
+			},
+			"data_used_bytes": schema.Int64Attribute{
+				Description: "Storage used by the records of the set. Null before server 7",
+				Computed:    true,
+			},
+			"memory_data_bytes": schema.Int64Attribute{
+				Description: "Memory used by the records of the set. Null from server 7",
+				Computed:    true,
+			},
+			"device_data_bytes": schema.Int64Attribute{
+				Description: "Device storage used by the records of the set. Null from server 7",
+				Computed:    true,
+			},
+			"default_ttl": schema.Int64Attribute{
+				Description: "Default TTL of the set in seconds, 0 when the namespace default-ttl is used. Null before server 7.1",
+				Computed:    true,
+			},
+			"stop_writes_count": schema.Int64Attribute{
+				Description: "Number of records at which writes to the set stop, 0 for no limit",
+				Computed:    true,
+			},
+			"stop_writes_size": schema.Int64Attribute{
+				Description: "Size in bytes at which writes to the set stop, 0 for no limit. Null before server 7",
+				Computed:    true,
+			},
+			"index_enabled": schema.BoolAttribute{
+				Description: "Whether the set has a set index (enable-index). Null before server 6.1",
+				Computed:    true,
+			},
+			"index_populating": schema.BoolAttribute{
+				Description: "Whether the set index is still being built on any node. Null before server 6.1",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *AerospikeSetDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_set is not supported",
+			"aerospike_set uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeSetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeSetDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	set := data.Set.ValueString()
+
+	resp.Diagnostics.Append(d.asConn.checkNamespace(ctx, path.Root("namespace"), namespace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	responses, err := d.asConn.infoAllNodes(ctx, "sets/"+namespace+"/"+set)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading set statistics", err.Error())
+		return
+	}
+
+	sums, config, found := mergeSetInfo(responses)
+	if !found {
+		resp.Diagnostics.AddAttributeError(path.Root("set"), "Set does not exist",
+			fmt.Sprintf("Namespace %s has no set %s. Sets are created by the first record written to them", namespace, set))
+		return
+	}
+
+	data.Objects = int64OrNull(sums, "objects")
+	data.Tombstones = int64OrNull(sums, "tombstones")
+	data.Data_used_bytes = int64OrNull(sums, "data_used_bytes")
+	data.Memory_data_bytes = int64OrNull(sums, "memory_data_bytes")
+	data.Device_data_bytes = int64OrNull(sums, "device_data_bytes")
+	data.Default_ttl = parseInt64OrNull(config["default-ttl"])
+	data.Stop_writes_count = parseInt64OrNull(config["stop-writes-count"])
+	data.Stop_writes_size = parseInt64OrNull(config["stop-writes-size"])
+	data.Index_enabled = parseBoolOrNull(config["enable-index"])
+	data.Index_populating = types.BoolNull()
+	for _, response := range responses {
+		if populating, ok := parseSetInfo(response)["index_populating"]; ok {
+			data.Index_populating = types.BoolValue(data.Index_populating.ValueBool() || populating == "true")
+		}
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read set %s.%s on %d nodes", namespace, set, len(responses)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseSetInfo parses a node's response to sets/<namespace>/<set>, whose values are separated by colons.
+func parseSetInfo(response string) map[string]string {
+	params := make(map[string]string)
+	for _, kv := range strings.Split(strings.TrimSuffix(strings.TrimSpace(response), ";"), ":") {
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		params[k] = v
+	}
+
+	return params
+}
+
+// mergeSetInfo sums the set statistics over the nodes and returns the configuration of the first node, by name,
+// that has the set. found is false when no node has the set.
+func mergeSetInfo(responses map[string]string) (sums map[string]int64, config map[string]string, found bool) {
+	sums = make(map[string]int64)
+	for _, node := range sortedKeys(responses) {
+		values := parseSetInfo(responses[node])
+		if _, ok := values["objects"]; !ok {
+			continue
+		}
+
+		if !found {
+			config = values
+			found = true
+		}
+		for _, stat := range setStatistics {
+			if n, err := strconv.ParseInt(values[stat], 10, 64); err == nil {
+				sums[stat] += n
+			}
+		}
+	}
+
+	return sums, config, found
+}
+
+// int64OrNull returns m[key], or null if m doesn't have key.
+func int64OrNull(m map[string]int64, key string) types.Int64 {
+	if n, ok := m[key]; ok {
+		return types.Int64Value(n)
+	}
+
+	return types.Int64Null()
+}
+
+// parseInt64OrNull parses s, returning null if it isn't a number, e.g. when the server doesn't report the value.
+func parseInt64OrNull(s string) types.Int64 {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return types.Int64Value(n)
+	}
+
+	return types.Int64Null()
+}
+
+// parseBoolOrNull parses s, returning null if it isn't true or false.
+func parseBoolOrNull(s string) types.Bool {
+	if b, err := strconv.ParseBool(s); err == nil {
+		return types.BoolValue(b)
+	}
+
+	return types.BoolNull()
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeSetDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `resource "aerospike_record" "test" {
+  namespace = "aerospike"
+  set       = "tf_set_data_source"
+  key       = "k1"
+  bins = {
+    value = "1"
+  }
+}
+
+data "aerospike_set" "test" {
+  namespace = aerospike_record.test.namespace
+  set       = aerospike_record.test.set
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.aerospike_set.test", "objects"),
+					resource.TestCheckResourceAttrSet("data.aerospike_set.test", "stop_writes_count"),
+				),
+			},
+		},
+	})
+}
+
+func TestMergeSetInfo(t *testing.T) {
+	responses := map[string]string{
+		"BB902": "objects=3:tombstones=0:data_used_bytes=300:truncate_lut=0:index_populating=false:default-ttl=0:" +
+			"enable-index=true:stop-writes-count=0:stop-writes-size=0;",
+		"BB901": "objects=2:tombstones=1:data_used_bytes=200:truncate_lut=0:index_populating=false:default-ttl=0:" +
+			"enable-index=true:stop-writes-count=0:stop-writes-size=0;",
+		"BB903": "",
+	}
+
+	sums, config, found := mergeSetInfo(responses)
+	if !found {
+		t.Fatal("mergeSetInfo() didn't find the set")
+	}
+	want := map[string]int64{"objects": 5, "tombstones": 1, "data_used_bytes": 500}
+	if !reflect.DeepEqual(sums, want) {
+		t.Errorf("mergeSetInfo() sums = %v, want %v", sums, want)
+	}
+	if config["enable-index"] != "true" || config["objects"] != "2" {
+		t.Errorf("mergeSetInfo() config = %v, want the values of node BB901", config)
+	}
+
+	if _, _, found := mergeSetInfo(map[string]string{"BB901": ""}); found {
+		t.Error("mergeSetInfo() found a set no node has")
+	}
+}
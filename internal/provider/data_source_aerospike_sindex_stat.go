@@ -0,0 +1,277 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeSindexStatDataSource{}
+
+func NewAerospikeSindexStatDataSource() datasource.DataSource {
+	return &AerospikeSindexStatDataSource{}
+}
+
+// AerospikeSindexStatDataSource defines the data source implementation.
+type AerospikeSindexStatDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeSindexStatDataSourceModel describes the data source data model.
+type AerospikeSindexStatDataSourceModel struct {
+	Namespace  types.String               `tfsdk:"namespace"`
+	Name       types.String               `tfsdk:"name"`
+	Entries    types.Int64                `tfsdk:"entries"`
+	Used_bytes types.Int64                `tfsdk:"used_bytes"`
+	Load_pct   types.Int64                `tfsdk:"load_pct"`
+	State      types.String               `tfsdk:"state"`
+	Ready      types.Bool                 `tfsdk:"ready"`
+	Nodes      []AerospikeNodeSindexModel `tfsdk:"nodes"`
+}
+
+type AerospikeNodeSindexModel struct {
+	Node       types.String `tfsdk:"node"`
+	Entries    types.Int64  `tfsdk:"entries"`
+	Used_bytes types.Int64  `tfsdk:"used_bytes"`
+	Load_pct   types.Int64  `tfsdk:"load_pct"`
+	State      types.String `tfsdk:"state"`
+}
+
+// sindexStat is the status of a secondary index on a node.
+type sindexStat struct {
+	entries   int64
+	usedBytes int64
+	loadPct   int64
+	state     string
+}
+
+// sindexReadyState is the state of an index that is fully built and can be queried.
+const sindexReadyState = "RW"
+
+func (d *AerospikeSindexStatDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sindex_stat"
+}
+
+func (d *AerospikeSindexStatDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Statistics of a secondary index on every node, e.g. to check that an index finished building before it is used",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Namespace of the index",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Index name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"entries": schema.Int64Attribute{
+				Description: "Number of index entries, summed over the nodes",
+				Computed:    true,
+			},
+			"used_bytes": schema.Int64Attribute{
+				Description: "Memory used by the index, summed over the nodes",
+				Computed:    true,
+			},
+			"load_pct": schema.Int64Attribute{
+				Description: "Lowest percentage of the index built on a node. 100 when the index is built on every node",
+				Computed:    true,
+			},
+			"state": schema.StringAttribute{
+				Description: "State of the index, RW when it is ready on every node. Otherwise the state of the first node, " +
+					"by name, where it isn't ready, e.g. WO while the index is being built",
+				Computed: true,
+			},
+			"ready": schema.BoolAttribute{
+				Description: "Whether the index is built and queryable on every node",
+				Computed:    true,
+			},
+			"nodes": schema.ListNestedAttribute{
+				Description: "Index statistics per node, sorted by node name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Description: "Node name",
+							Computed:    true,
+						},
+						"entries": schema.Int64Attribute{
+							Description: "Number of index entries on the node",
+							Computed:    true,
+						},
+						"used_bytes": schema.Int64Attribute{
+							Description: "Memory used by the index on the node",
+							Computed:    true,
+						},
+						"load_pct": schema.Int64Attribute{
+							Description: "Percentage of the index built on the node",
+							Computed:    true,
+						},
+						"state": schema.StringAttribute{
+							Description: "State of the index on the node",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AerospikeSindexStatDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_sindex_stat is not supported",
+			"aerospike_sindex_stat uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeSindexStatDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeSindexStatDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+	name := data.Name.ValueString()
+
+	resp.Diagnostics.Append(d.asConn.checkNamespace(ctx, path.Root("namespace"), namespace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stats, err := d.asConn.infoAllNodes(ctx, "sindex-stat:namespace="+namespace+";indexname="+name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading index statistics", err.Error())
+		return
+	}
+	lists, err := d.asConn.infoAllNodes(ctx, "sindex-list:ns="+namespace)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading index states", err.Error())
+		return
+	}
+
+	data.Entries = types.Int64Value(0)
+	data.Used_bytes = types.Int64Value(0)
+	data.Load_pct = types.Int64Value(100)
+	data.State = types.StringValue(sindexReadyState)
+	data.Nodes = make([]AerospikeNodeSindexModel, 0, len(stats))
+	for _, node := range sortedKeys(stats) {
+		if isInfoError(stats[node]) {
+			resp.Diagnostics.AddAttributeError(path.Root("name"), "Index does not exist",
+				fmt.Sprintf("Node %s has no index %s in namespace %s: %s", node, name, namespace, stats[node]))
+			return
+		}
+
+		stat := parseSindexStat(stats[node], sindexListEntry(lists[node], name))
+		data.Entries = types.Int64Value(data.Entries.ValueInt64() + stat.entries)
+		data.Used_bytes = types.Int64Value(data.Used_bytes.ValueInt64() + stat.usedBytes)
+		data.Load_pct = types.Int64Value(min(data.Load_pct.ValueInt64(), stat.loadPct))
+		if data.State.ValueString() == sindexReadyState && stat.state != sindexReadyState {
+			data.State = types.StringValue(stat.state)
+		}
+
+		data.Nodes = append(data.Nodes, AerospikeNodeSindexModel{
+			Node:       types.StringValue(node),
+			Entries:    types.Int64Value(stat.entries),
+			Used_bytes: types.Int64Value(stat.usedBytes),
+			Load_pct:   types.Int64Value(stat.loadPct),
+			State:      types.StringValue(stat.state),
+		})
+	}
+	data.Ready = types.BoolValue(data.State.ValueString() == sindexReadyState && data.Load_pct.ValueInt64() == 100)
+
+	tflog.Trace(ctx, fmt.Sprintf("read index %s of namespace %s on %d nodes", name, namespace, len(data.Nodes)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// sindexListEntry returns the values of index name in a node's response to sindex-list, or nil if the node doesn't
+// list it. Indexes are separated by semicolons and their values by colons.
+func sindexListEntry(response, name string) map[string]string {
+	for _, index := range strings.Split(strings.TrimSpace(response), ";") {
+		values := parseSetInfo(index)
+		if values["indexname"] == name {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// parseSindexStat returns the status of an index from a node's response to sindex-stat and the index's sindex-list
+// entry. Memory is reported as used_bytes from server 7, memory_used before, and as separate tree sizes in server 5.
+func parseSindexStat(response string, listEntry map[string]string) sindexStat {
+	values := parseInfoParams(response)
+	number := func(key string) int64 {
+		n, _ := strconv.ParseInt(values[key], 10, 64)
+		return n
+	}
+
+	stat := sindexStat{
+		entries: number("entries"),
+		loadPct: number("load_pct"),
+		state:   listEntry["state"],
+	}
+	switch {
+	case values["used_bytes"] != "":
+		stat.usedBytes = number("used_bytes")
+	case values["memory_used"] != "":
+		stat.usedBytes = number("memory_used")
+	default:
+		stat.usedBytes = number("ibtr_memory_used") + number("nbtr_memory_used")
+	}
+	if stat.state == "" {
+		stat.state = "unknown"
+	}
+
+	return stat
+}
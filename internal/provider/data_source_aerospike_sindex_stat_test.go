@@ -0,0 +1,35 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestParseSindexStat(t *testing.T) {
+	list := "ns=test:indexname=idx_age:set=users:bin=age:type=numeric:indextype=default:context=NULL:state=RW;" +
+		"ns=test:indexname=idx_name:set=users:bin=name:type=string:indextype=default:context=NULL:state=WO;"
+
+	cases := []struct {
+		name     string
+		response string
+		index    string
+		want     sindexStat
+	}{
+		{"server 7", "entries=1000;used_bytes=65536;entries_per_bval=1;entries_per_rec=1;load_pct=100;load_time=12",
+			"idx_age", sindexStat{entries: 1000, usedBytes: 65536, loadPct: 100, state: "RW"}},
+		{"server 6", "entries=10;memory_used=4096;load_pct=40", "idx_name",
+			sindexStat{entries: 10, usedBytes: 4096, loadPct: 40, state: "WO"}},
+		{"server 5", "keys=5;entries=5;ibtr_memory_used=1024;nbtr_memory_used=512;load_pct=100", "idx_age",
+			sindexStat{entries: 5, usedBytes: 1536, loadPct: 100, state: "RW"}},
+		{"not listed", "entries=0;used_bytes=0;load_pct=0", "idx_missing",
+			sindexStat{state: "unknown"}},
+	}
+
+	for _, c := range cases {
+		if got := parseSindexStat(c.response, sindexListEntry(list, c.index)); got != c.want {
+			t.Errorf("%s: parseSindexStat() = %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeStopWritesDataSource{}
+
+func NewAerospikeStopWritesDataSource() datasource.DataSource {
+	return &AerospikeStopWritesDataSource{}
+}
+
+// AerospikeStopWritesDataSource defines the data source implementation.
+type AerospikeStopWritesDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeStopWritesDataSourceModel describes the data source data model.
+type AerospikeStopWritesDataSourceModel struct {
+	Namespace   types.String                   `tfsdk:"namespace"`
+	Stop_writes types.Bool                     `tfsdk:"stop_writes"`
+	Reasons     []types.String                 `tfsdk:"reasons"`
+	Nodes       []AerospikeNodeStopWritesModel `tfsdk:"nodes"`
+}
+
+type AerospikeNodeStopWritesModel struct {
+	Node        types.String   `tfsdk:"node"`
+	Stop_writes types.Bool     `tfsdk:"stop_writes"`
+	Reasons     []types.String `tfsdk:"reasons"`
+}
+
+func (d *AerospikeStopWritesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_stop_writes"
+}
+
+func (d *AerospikeStopWritesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Stop-writes status of a namespace on every node. Useful for preconditions on resources that write data",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Namespace",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"stop_writes": schema.BoolAttribute{
+				Description: "Whether any node stopped accepting writes to the namespace",
+				Computed:    true,
+			},
+			"reasons": schema.ListAttribute{
+				Description: "Sorted list of the reasons for stop-writes across all nodes, e.g. clock-skew or stop-writes-avail-pct",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"nodes": schema.ListNestedAttribute{
+				Description: "Stop-writes status per node, sorted by node name",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"node": schema.StringAttribute{
+							Description: "Node name",
+							Computed:    true,
+						},
+						"stop_writes": schema.BoolAttribute{
+							Description: "Whether the node stopped accepting writes to the namespace",
+							Computed:    true,
+						},
+						"reasons": schema.ListAttribute{
+							Description: "Reasons for stop-writes on the node. unknown when the server reports stop-writes without a known cause",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AerospikeStopWritesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_stop_writes is not supported",
+			"aerospike_stop_writes uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeStopWritesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeStopWritesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.Namespace.ValueString()
+
+	nodes, err := d.asConn.namespaceStopWrites(ctx, namespace)
+	if errors.Is(err, errNamespaceNotFound) {
+		resp.Diagnostics.AddAttributeError(path.Root("namespace"), "Namespace does not exist", err.Error())
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading the stop-writes status", err.Error())
+		return
+	}
+
+	data.Stop_writes = types.BoolValue(false)
+	data.Nodes = make([]AerospikeNodeStopWritesModel, 0, len(nodes))
+	allReasons := make(map[string]bool)
+	for _, node := range nodes {
+		nodeModel := AerospikeNodeStopWritesModel{
+			Node:        types.StringValue(node.node),
+			Stop_writes: types.BoolValue(node.stopWrites),
+			Reasons:     make([]types.String, 0, len(node.reasons)),
+		}
+		for _, reason := range node.reasons {
+			nodeModel.Reasons = append(nodeModel.Reasons, types.StringValue(reason))
+			allReasons[reason] = true
+		}
+		if node.stopWrites {
+			data.Stop_writes = types.BoolValue(true)
+		}
+
+		data.Nodes = append(data.Nodes, nodeModel)
+	}
+
+	data.Reasons = make([]types.String, 0, len(allReasons))
+	for _, reason := range sortedKeys(allReasons) {
+		data.Reasons = append(data.Reasons, types.StringValue(reason))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read stop-writes status of namespace %s on %d nodes", namespace, len(data.Nodes)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// errNamespaceNotFound is returned by namespaceStopWrites when a node doesn't have the namespace.
+var errNamespaceNotFound = errors.New("namespace does not exist")
+
+// nodeStopWrites is the stop-writes status of a namespace on a node.
+type nodeStopWrites struct {
+	node       string
+	stopWrites bool
+	reasons    []string
+}
+
+// namespaceStopWrites returns the stop-writes status of a namespace on every node, sorted by node name.
+func (c *asConnection) namespaceStopWrites(ctx context.Context, namespace string) ([]nodeStopWrites, error) {
+	configs, err := c.infoAllNodes(ctx, getConfigCommand("namespace", namespace, ""))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the namespace configuration: %w", err)
+	}
+	stats, err := c.infoAllNodes(ctx, "namespace/"+namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the namespace statistics: %w", err)
+	}
+
+	nodes := make([]nodeStopWrites, 0, len(stats))
+	for _, node := range sortedKeys(stats) {
+		if isInfoError(configs[node]) {
+			return nil, fmt.Errorf("%w: node %s does not have the namespace %s: %s", errNamespaceNotFound, node, namespace, configs[node])
+		}
+
+		// config parameters are hyphenated and statistics use underscores, so they can share a map
+		values := parseInfoParams(configs[node])
+		for k, v := range parseInfoParams(stats[node]) {
+			values[k] = v
+		}
+
+		stopWrites := values["stop_writes"] == "true"
+		reasons := stopWritesReasons(values)
+		if stopWrites && len(reasons) == 0 {
+			reasons = []string{"unknown"}
+		}
+
+		nodes = append(nodes, nodeStopWrites{node: node, stopWrites: stopWrites, reasons: reasons})
+	}
+
+	return nodes, nil
+}
+
+// warnStopWrites returns a warning when namespace is in stop-writes on any node, so a plan that writes to it shows
+// it before the apply fails. The status is only informational, failing to read it is logged.
+func (c *asConnection) warnStopWrites(ctx context.Context, attrPath path.Path, namespace string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !c.supportsInfo() {
+		return diags
+	}
+
+	nodes, err := c.namespaceStopWrites(ctx, namespace)
+	if err != nil {
+		tflog.Warn(ctx, "unable to check stop-writes: "+err.Error(), map[string]interface{}{"namespace": namespace})
+		return diags
+	}
+
+	var stopped []string
+	for _, node := range nodes {
+		if node.stopWrites {
+			stopped = append(stopped, fmt.Sprintf("%s (%s)", node.node, strings.Join(node.reasons, ", ")))
+		}
+	}
+	if len(stopped) > 0 {
+		diags.AddAttributeWarning(attrPath, "Namespace is in stop-writes",
+			fmt.Sprintf("Namespace %s is in stop-writes on %s. Writes to it fail until it has room again, "+
+				"so this apply may fail", namespace, strings.Join(stopped, ", ")))
+	}
+
+	return diags
+}
+
+// stopWritesReasons returns the sorted stop-writes conditions met by a node's merged namespace statistics and
+// configuration. It covers the server 7 storage thresholds as well as the older memory and device thresholds.
+func stopWritesReasons(values map[string]string) []string {
+	var reasons []string
+
+	number := func(key string) (float64, bool) {
+		v, err := strconv.ParseFloat(values[key], 64)
+		return v, err == nil
+	}
+
+	if values["clock_skew_stop_writes"] == "true" {
+		reasons = append(reasons, "clock-skew")
+	}
+
+	// server 7 and later
+	if avail, ok := number("data_avail_pct"); ok {
+		if limit, ok := number("storage-engine.stop-writes-avail-pct"); ok && limit > 0 && avail <= limit {
+			reasons = append(reasons, "stop-writes-avail-pct")
+		}
+	}
+	if used, ok := number("data_used_pct"); ok {
+		if limit, ok := number("storage-engine.stop-writes-used-pct"); ok && limit > 0 && used >= limit {
+			reasons = append(reasons, "stop-writes-used-pct")
+		}
+	}
+
+	// before server 7
+	if avail, ok := number("device_available_pct"); ok {
+		if limit, ok := number("storage-engine.min-avail-pct"); ok && limit > 0 && avail <= limit {
+			reasons = append(reasons, "min-avail-pct")
+		}
+	}
+	if used, ok := number("memory_used_bytes"); ok {
+		size, sizeOk := number("memory-size")
+		if limit, ok := number("stop-writes-pct"); ok && sizeOk && size > 0 && limit > 0 && used*100/size >= limit {
+			reasons = append(reasons, "stop-writes-pct")
+		}
+	}
+
+	sort.Strings(reasons)
+
+	return reasons
+}
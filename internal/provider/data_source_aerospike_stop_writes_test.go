@@ -0,0 +1,79 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeStopWritesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "aerospike_stop_writes" "test" {
+  namespace = "aerospike"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_stop_writes.test", "stop_writes", "false"),
+					resource.TestCheckResourceAttr("data.aerospike_stop_writes.test", "reasons.#", "0"),
+					resource.TestCheckResourceAttrSet("data.aerospike_stop_writes.test", "nodes.0.node"),
+				),
+			},
+		},
+	})
+}
+
+func TestStopWritesReasons(t *testing.T) {
+	cases := []struct {
+		name   string
+		values map[string]string
+		want   []string
+	}{
+		{"healthy", map[string]string{
+			"data_avail_pct":                       "80",
+			"storage-engine.stop-writes-avail-pct": "5",
+			"data_used_pct":                        "20",
+			"storage-engine.stop-writes-used-pct":  "70",
+		}, nil},
+		{"server 7 thresholds", map[string]string{
+			"clock_skew_stop_writes":               "true",
+			"data_avail_pct":                       "4",
+			"storage-engine.stop-writes-avail-pct": "5",
+			"data_used_pct":                        "75",
+			"storage-engine.stop-writes-used-pct":  "70",
+		}, []string{"clock-skew", "stop-writes-avail-pct", "stop-writes-used-pct"}},
+		{"older thresholds", map[string]string{
+			"device_available_pct":         "3",
+			"storage-engine.min-avail-pct": "5",
+			"memory_used_bytes":            "950",
+			"memory-size":                  "1000",
+			"stop-writes-pct":              "90",
+		}, []string{"min-avail-pct", "stop-writes-pct"}},
+		{"disabled threshold", map[string]string{
+			"data_used_pct":                       "100",
+			"storage-engine.stop-writes-used-pct": "0",
+		}, nil},
+	}
+
+	for _, c := range cases {
+		if got := stopWritesReasons(c.values); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: stopWritesReasons() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestWarnStopWrites(t *testing.T) {
+	// the fake client has no nodes to read the status from, which must not fail the plan
+	conn := newFakeConnection(newFakeClient(), "admin")
+	if diags := conn.warnStopWrites(context.Background(), path.Root("namespace"), "test"); len(diags) != 0 {
+		t.Errorf("warnStopWrites() = %v, want no diagnostics when the status can't be read", diags)
+	}
+}
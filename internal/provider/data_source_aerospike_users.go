@@ -0,0 +1,133 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"sort"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AerospikeUsersDataSource{}
+
+func NewAerospikeUsersDataSource() datasource.DataSource {
+	return &AerospikeUsersDataSource{}
+}
+
+// AerospikeUsersDataSource defines the data source implementation.
+type AerospikeUsersDataSource struct {
+	asConn *asConnection
+}
+
+// AerospikeUsersDataSourceModel describes the data source data model.
+type AerospikeUsersDataSourceModel struct {
+	Name_prefix types.String   `tfsdk:"name_prefix"`
+	Name_regex  types.String   `tfsdk:"name_regex"`
+	User_names  []types.String `tfsdk:"user_names"`
+}
+
+func (d *AerospikeUsersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *AerospikeUsersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Names of the users defined in the cluster. Useful for generating import blocks for existing users",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Description: "Only return users whose name starts with this prefix",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return users whose name matches this regular expression",
+				Optional:    true,
+			},
+			"user_names": schema.ListAttribute{
+				Description: "Sorted list of matching user names",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *AerospikeUsersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_users")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	d.asConn = asConn
+}
+
+func (d *AerospikeUsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AerospikeUsersDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	matches, err := nameMatcher(data.Name_prefix.ValueString(), data.Name_regex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+		return
+	}
+
+	users, asErr := d.asConn.getClient(ctx).QueryUsers(d.asConn.adminPolicy)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error querying users", asErr.Error())
+		return
+	}
+
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		if matches(u.User) {
+			names = append(names, u.User)
+		}
+	}
+	sort.Strings(names)
+
+	data.User_names = make([]types.String, 0, len(names))
+	for _, n := range names {
+		data.User_names = append(data.User_names, types.StringValue(n))
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d matching users", len(names)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeUsersDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_user" "testdsuser1" {
+  user_name = "testdsuser1"
+  password  = "testpass1"
+}
+
+data "aerospike_users" "test" {
+  name_prefix = "testds"
+  depends_on  = [aerospike_user.testdsuser1]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.aerospike_users.test", "user_names.#", "1"),
+					resource.TestCheckResourceAttr("data.aerospike_users.test", "user_names.0", "testdsuser1"),
+				),
+			},
+		},
+	})
+}
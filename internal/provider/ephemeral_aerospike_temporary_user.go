@@ -0,0 +1,192 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &AerospikeTemporaryUser{}
+var _ ephemeral.EphemeralResourceWithConfigure = &AerospikeTemporaryUser{}
+var _ ephemeral.EphemeralResourceWithClose = &AerospikeTemporaryUser{}
+
+const temporaryUserPrivateKey = "user_name"
+
+func NewAerospikeTemporaryUser() ephemeral.EphemeralResource {
+	return &AerospikeTemporaryUser{}
+}
+
+// AerospikeTemporaryUser defines the ephemeral resource implementation.
+type AerospikeTemporaryUser struct {
+	asConn *asConnection
+}
+
+// AerospikeTemporaryUserModel describes the ephemeral resource data model.
+type AerospikeTemporaryUserModel struct {
+	Name_prefix types.String   `tfsdk:"name_prefix"`
+	Roles       []types.String `tfsdk:"roles"`
+	User_name   types.String   `tfsdk:"user_name"`
+	Password    types.String   `tfsdk:"password"`
+}
+
+func (r *AerospikeTemporaryUser) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_temporary_user"
+}
+
+func (r *AerospikeTemporaryUser) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Aerospike user that exists only for the duration of a terraform run. The user is created with a random name and password and dropped when the run ends",
+
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Description: "Prefix for the generated user name. Defaults to \"tf-tmp-\"",
+				Optional:    true,
+			},
+			"roles": schema.ListAttribute{
+				Description: "Roles that should be granted to the user",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"user_name": schema.StringAttribute{
+				Description: "Generated user name",
+				Computed:    true,
+			},
+			"password": schema.StringAttribute{
+				Description: "Generated password",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+func (r *AerospikeTemporaryUser) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_temporary_user")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeTemporaryUser) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_temporary_user")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeTemporaryUserModel
+
+	// Read Terraform config data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prefix := "tf-tmp-"
+	if !data.Name_prefix.IsNull() {
+		prefix = data.Name_prefix.ValueString()
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating user name", err.Error())
+		return
+	}
+	password, err := randomHex(16)
+	if err != nil {
+		resp.Diagnostics.AddError("Error generating password", err.Error())
+		return
+	}
+	userName := prefix + suffix
+
+	roles := make([]string, 0, len(data.Roles))
+	for _, r := range data.Roles {
+		roles = append(roles, r.ValueString())
+	}
+
+	asErr := r.asConn.getClient(ctx).CreateUser(r.asConn.adminPolicy, userName, password, roles)
+	if asErr != nil {
+		resp.Diagnostics.AddError("Error creating temporary user", asErr.Error())
+		return
+	}
+
+	// Remember the user so Close can drop it
+	privateData, _ := json.Marshal(userName)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, temporaryUserPrivateKey, privateData)...)
+
+	tflog.Trace(ctx, "created temporary user "+userName+" with roles "+strings.Join(roles, ", "))
+
+	data.User_name = types.StringValue(userName)
+	data.Password = types.StringValue(password)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *AerospikeTemporaryUser) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	privateData, diags := req.Private.GetKey(ctx, temporaryUserPrivateKey)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() || privateData == nil {
+		return
+	}
+
+	var userName string
+	if err := json.Unmarshal(privateData, &userName); err != nil {
+		resp.Diagnostics.AddError("Error reading temporary user name", err.Error())
+		return
+	}
+
+	asErr := r.asConn.getClient(ctx).DropUser(r.asConn.adminPolicy, userName)
+	if asErr != nil && !asErr.Matches(astypes.INVALID_USER) {
+		resp.Diagnostics.AddError("Error dropping temporary user", asErr.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "dropped temporary user "+userName)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// dropFailsClient is a fake client that can't drop users.
+type dropFailsClient struct {
+	*fakeClient
+}
+
+func (c *dropFailsClient) DropUser(policy *as.AdminPolicy, user string) as.Error {
+	c.calls = append(c.calls, "DropUser "+user)
+	return fakeError(astypes.ROLE_VIOLATION)
+}
+
+// openTemporaryUser opens an aerospike_temporary_user with name prefix "tmp-" and role read, and returns the
+// response with its private state.
+func openTemporaryUser(t *testing.T, r *AerospikeTemporaryUser) *ephemeral.OpenResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	req := ephemeral.OpenRequest{Config: tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"name_prefix": tftypes.NewValue(tftypes.String, "tmp-"),
+			"roles":       tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "read")}),
+			"user_name":   tftypes.NewValue(tftypes.String, nil),
+			"password":    tftypes.NewValue(tftypes.String, nil),
+		}),
+	}}
+	resp := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, nil),
+	}}
+	// the framework doesn't export the type of the private state, create an empty one as it does
+	reflect.ValueOf(&resp.Private).Elem().Set(reflect.New(reflect.TypeOf(resp.Private).Elem()))
+
+	r.Open(ctx, req, resp)
+
+	return resp
+}
+
+func TestTemporaryUserOpenClose(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	r := &AerospikeTemporaryUser{asConn: newFakeConnection(client, "admin")}
+
+	resp := openTemporaryUser(t, r)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open() returned errors: %v", resp.Diagnostics)
+	}
+
+	var data AerospikeTemporaryUserModel
+	if diags := resp.Result.Get(ctx, &data); diags.HasError() {
+		t.Fatalf("reading the result: %v", diags)
+	}
+	userName := data.User_name.ValueString()
+	if !strings.HasPrefix(userName, "tmp-") || data.Password.ValueString() == "" {
+		t.Errorf("Open() user_name = %q, password set = %v, want a tmp- user with a password", userName, data.Password.ValueString() != "")
+	}
+	user, ok := client.users[userName]
+	if !ok || !reflect.DeepEqual(user.Roles, []string{"read"}) {
+		t.Fatalf("users = %v, want %s with role read", client.users, userName)
+	}
+
+	privateData, diags := resp.Private.GetKey(ctx, temporaryUserPrivateKey)
+	if diags.HasError() || string(privateData) != `"`+userName+`"` {
+		t.Errorf("private %s = %s, want the user name %q", temporaryUserPrivateKey, privateData, userName)
+	}
+
+	// Close drops the user named by the private state
+	var closeResp ephemeral.CloseResponse
+	r.Close(ctx, ephemeral.CloseRequest{Private: resp.Private}, &closeResp)
+	if closeResp.Diagnostics.HasError() {
+		t.Fatalf("Close() returned errors: %v", closeResp.Diagnostics)
+	}
+	if _, ok := client.users[userName]; ok {
+		t.Errorf("Close() didn't drop %s", userName)
+	}
+
+	// a user that is already gone isn't an error
+	closeResp = ephemeral.CloseResponse{}
+	r.Close(ctx, ephemeral.CloseRequest{Private: resp.Private}, &closeResp)
+	if closeResp.Diagnostics.HasError() {
+		t.Errorf("Close() of a dropped user returned errors: %v", closeResp.Diagnostics)
+	}
+}
+
+func TestTemporaryUserCloseError(t *testing.T) {
+	ctx := context.Background()
+	client := &dropFailsClient{newFakeClient()}
+	r := &AerospikeTemporaryUser{asConn: newFakeConnection(client.fakeClient, "admin")}
+
+	resp := openTemporaryUser(t, r)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Open() returned errors: %v", resp.Diagnostics)
+	}
+
+	r.asConn.client = client
+	var closeResp ephemeral.CloseResponse
+	r.Close(ctx, ephemeral.CloseRequest{Private: resp.Private}, &closeResp)
+	if !closeResp.Diagnostics.HasError() {
+		t.Errorf("Close() succeeded although the user couldn't be dropped, want an error")
+	}
+	if len(client.users) != 1 {
+		t.Errorf("users = %v, want the temporary user kept", client.users)
+	}
+}
+
+func TestTemporaryUserOpenReadOnly(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeTemporaryUser{asConn: newFakeConnection(client, "admin")}
+	r.asConn.readOnly = true
+
+	resp := openTemporaryUser(t, r)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("Open() with a read only provider succeeded, want an error")
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls = %v, want none", client.calls)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"sort"
+)
+
+// fakeClient is an in-memory aerospikeClient for unit tests. It keeps users and roles in maps and records the admin
+// calls made so tests can assert on them.
+type fakeClient struct {
+	users   map[string]*as.UserRoles
+	roles   map[string]*as.Role
+	records map[string]as.BinMap
+	calls   []string
+}
+
+var _ aerospikeClient = &fakeClient{}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		users:   make(map[string]*as.UserRoles),
+		roles:   make(map[string]*as.Role),
+		records: make(map[string]as.BinMap),
+	}
+}
+
+// newFakeConnection returns a connection that uses a fake client and no info commands.
+func newFakeConnection(client *fakeClient, userName string) *asConnection {
+	return &asConnection{
+		client:          client,
+		adminPolicy:     as.NewAdminPolicy(),
+		userName:        userName,
+		enterprise:      true,
+		securityEnabled: true,
+	}
+}
+
+func fakeError(code astypes.ResultCode) as.Error {
+	return &as.AerospikeError{ResultCode: code}
+}
+
+func (c *fakeClient) IsConnected() bool    { return true }
+func (c *fakeClient) Close()               {}
+func (c *fakeClient) GetNodes() []*as.Node { return nil }
+
+func (c *fakeClient) CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) as.Error {
+	c.calls = append(c.calls, "CreateUser "+user)
+	if _, ok := c.users[user]; ok {
+		return fakeError(astypes.USER_ALREADY_EXISTS)
+	}
+	c.users[user] = &as.UserRoles{User: user, Roles: append([]string{}, roles...)}
+
+	return nil
+}
+
+func (c *fakeClient) DropUser(policy *as.AdminPolicy, user string) as.Error {
+	c.calls = append(c.calls, "DropUser "+user)
+	if _, ok := c.users[user]; !ok {
+		return fakeError(astypes.INVALID_USER)
+	}
+	delete(c.users, user)
+
+	return nil
+}
+
+func (c *fakeClient) ChangePassword(policy *as.AdminPolicy, user string, password string) as.Error {
+	c.calls = append(c.calls, "ChangePassword "+user)
+	if _, ok := c.users[user]; !ok {
+		return fakeError(astypes.INVALID_USER)
+	}
+
+	return nil
+}
+
+func (c *fakeClient) GrantRoles(policy *as.AdminPolicy, user string, roles []string) as.Error {
+	c.calls = append(c.calls, "GrantRoles "+user)
+	u, ok := c.users[user]
+	if !ok {
+		return fakeError(astypes.INVALID_USER)
+	}
+	u.Roles = append(u.Roles, roles...)
+
+	return nil
+}
+
+func (c *fakeClient) RevokeRoles(policy *as.AdminPolicy, user string, roles []string) as.Error {
+	c.calls = append(c.calls, "RevokeRoles "+user)
+	u, ok := c.users[user]
+	if !ok {
+		return fakeError(astypes.INVALID_USER)
+	}
+	u.Roles = removeAll(u.Roles, roles)
+
+	return nil
+}
+
+func (c *fakeClient) QueryUser(policy *as.AdminPolicy, user string) (*as.UserRoles, as.Error) {
+	u, ok := c.users[user]
+	if !ok {
+		return nil, fakeError(astypes.INVALID_USER)
+	}
+
+	return u, nil
+}
+
+func (c *fakeClient) QueryUsers(policy *as.AdminPolicy) ([]*as.UserRoles, as.Error) {
+	users := make([]*as.UserRoles, 0, len(c.users))
+	for _, name := range sortedKeys(c.users) {
+		users = append(users, c.users[name])
+	}
+
+	return users, nil
+}
+
+func (c *fakeClient) CreateRole(policy *as.AdminPolicy, roleName string, privileges []as.Privilege, whitelist []string, readQuota, writeQuota uint32) as.Error {
+	c.calls = append(c.calls, "CreateRole "+roleName)
+	if _, ok := c.roles[roleName]; ok {
+		return fakeError(astypes.ROLE_ALREADY_EXISTS)
+	}
+	c.roles[roleName] = &as.Role{
+		Name:       roleName,
+		Privileges: append([]as.Privilege{}, privileges...),
+		Whitelist:  append([]string{}, whitelist...),
+		ReadQuota:  readQuota,
+		WriteQuota: writeQuota,
+	}
+
+	return nil
+}
+
+func (c *fakeClient) DropRole(policy *as.AdminPolicy, roleName string) as.Error {
+	c.calls = append(c.calls, "DropRole "+roleName)
+	if _, ok := c.roles[roleName]; !ok {
+		return fakeError(astypes.INVALID_ROLE)
+	}
+	delete(c.roles, roleName)
+
+	return nil
+}
+
+func (c *fakeClient) GrantPrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error {
+	c.calls = append(c.calls, "GrantPrivileges "+roleName)
+	r, ok := c.roles[roleName]
+	if !ok {
+		return fakeError(astypes.INVALID_ROLE)
+	}
+	r.Privileges = append(r.Privileges, privileges...)
+
+	return nil
+}
+
+func (c *fakeClient) RevokePrivileges(policy *as.AdminPolicy, roleName string, privileges []as.Privilege) as.Error {
+	c.calls = append(c.calls, "RevokePrivileges "+roleName)
+	r, ok := c.roles[roleName]
+	if !ok {
+		return fakeError(astypes.INVALID_ROLE)
+	}
+	r.Privileges = removeAll(r.Privileges, privileges)
+
+	return nil
+}
+
+func (c *fakeClient) SetWhitelist(policy *as.AdminPolicy, roleName string, whitelist []string) as.Error {
+	c.calls = append(c.calls, "SetWhitelist "+roleName)
+	r, ok := c.roles[roleName]
+	if !ok {
+		return fakeError(astypes.INVALID_ROLE)
+	}
+	r.Whitelist = append([]string{}, whitelist...)
+
+	return nil
+}
+
+func (c *fakeClient) SetQuotas(policy *as.AdminPolicy, roleName string, readQuota, writeQuota uint32) as.Error {
+	c.calls = append(c.calls, "SetQuotas "+roleName)
+	r, ok := c.roles[roleName]
+	if !ok {
+		return fakeError(astypes.INVALID_ROLE)
+	}
+	r.ReadQuota = readQuota
+	r.WriteQuota = writeQuota
+
+	return nil
+}
+
+func (c *fakeClient) QueryRole(policy *as.AdminPolicy, role string) (*as.Role, as.Error) {
+	r, ok := c.roles[role]
+	if !ok {
+		return nil, fakeError(astypes.INVALID_ROLE)
+	}
+
+	return r, nil
+}
+
+func (c *fakeClient) QueryRoles(policy *as.AdminPolicy) ([]*as.Role, as.Error) {
+	roles := make([]*as.Role, 0, len(c.roles))
+	for _, name := range sortedKeys(c.roles) {
+		roles = append(roles, c.roles[name])
+	}
+
+	return roles, nil
+}
+
+func (c *fakeClient) Put(policy *as.WritePolicy, key *as.Key, binMap as.BinMap) as.Error {
+	id := fakeRecordID(key)
+	bins, ok := c.records[id]
+	if ok && policy != nil && policy.RecordExistsAction == as.CREATE_ONLY {
+		return fakeError(astypes.KEY_EXISTS_ERROR)
+	}
+	if !ok {
+		bins = make(as.BinMap)
+		c.records[id] = bins
+	}
+	for k, v := range binMap {
+		if v == nil {
+			delete(bins, k)
+			continue
+		}
+		bins[k] = v
+	}
+
+	return nil
+}
+
+func (c *fakeClient) Get(policy *as.BasePolicy, key *as.Key, binNames ...string) (*as.Record, as.Error) {
+	bins, ok := c.records[fakeRecordID(key)]
+	if !ok {
+		return nil, fakeError(astypes.KEY_NOT_FOUND_ERROR)
+	}
+
+	return &as.Record{Key: key, Bins: bins}, nil
+}
+
+func (c *fakeClient) Delete(policy *as.WritePolicy, key *as.Key) (bool, as.Error) {
+	id := fakeRecordID(key)
+	_, ok := c.records[id]
+	delete(c.records, id)
+
+	return ok, nil
+}
+
+// BatchOperate supports batch reads and deletes. The operations of a batch write can't be inspected, so batch
+// writes fail with a parameter error.
+func (c *fakeClient) BatchOperate(policy *as.BatchPolicy, records []as.BatchRecordIfc) as.Error {
+	for _, r := range records {
+		rec := r.BatchRec()
+		switch r.(type) {
+		case *as.BatchRead:
+			bins, ok := c.records[fakeRecordID(rec.Key)]
+			if !ok {
+				rec.ResultCode = astypes.KEY_NOT_FOUND_ERROR
+				continue
+			}
+			rec.ResultCode = astypes.OK
+			rec.Record = &as.Record{Key: rec.Key, Bins: bins, Generation: 1}
+		case *as.BatchDelete:
+			if ok, _ := c.Delete(nil, rec.Key); !ok {
+				rec.ResultCode = astypes.KEY_NOT_FOUND_ERROR
+				continue
+			}
+			rec.ResultCode = astypes.OK
+		default:
+			rec.ResultCode = astypes.PARAMETER_ERROR
+		}
+	}
+
+	return nil
+}
+
+func fakeRecordID(key *as.Key) string {
+	return key.Namespace() + ":" + key.SetName() + ":" + key.Value().String()
+}
+
+// removeAll returns items without any of the values in remove.
+func removeAll[T comparable](items, remove []T) []T {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		found := false
+		for _, r := range remove {
+			if item == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// sortedCalls returns the recorded calls sorted, for order independent assertions.
+func (c *fakeClient) sortedCalls() []string {
+	calls := append([]string{}, c.calls...)
+	sort.Strings(calls)
+
+	return calls
+}
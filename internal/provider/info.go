@@ -0,0 +1,691 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errInfoNotSupported is returned by the info helpers when connected through the proxy client.
+var errInfoNotSupported = errors.New("info commands are not supported with client_type = \"proxy\" (Aerospike Cloud)")
+
+// info_target values, which pick the node infoAnyNode sends commands to.
+const (
+	infoTargetAny    = "any"
+	infoTargetSeed   = "seed"
+	infoTargetRandom = "random"
+	infoTargetQuorum = "quorum"
+)
+
+// newInfoPolicy returns an info policy with the timeout in seconds, or the client default timeout for 0.
+func newInfoPolicy(timeoutSeconds int64) *as.InfoPolicy {
+	infoPol := as.NewInfoPolicy()
+	if timeoutSeconds != 0 {
+		infoPol.Timeout = time.Second * time.Duration(timeoutSeconds)
+	}
+
+	return infoPol
+}
+
+// infoAllNodes sends an info command to every node in the cluster and returns the responses keyed by node name.
+func (c *asConnection) infoAllNodes(ctx context.Context, command string) (map[string]string, error) {
+	return c.infoAllNodesWithPolicy(ctx, c.infoPolicy, command)
+}
+
+// infoAllNodesWithPolicy is infoAllNodes with a specific info policy, e.g. a resource's own timeout.
+func (c *asConnection) infoAllNodesWithPolicy(ctx context.Context, infoPol *as.InfoPolicy, command string) (map[string]string, error) {
+	if !c.supportsInfo() {
+		return nil, errInfoNotSupported
+	}
+
+	if infoPol == nil {
+		infoPol = as.NewInfoPolicy()
+	}
+
+	nodes := c.getClient(ctx).GetNodes()
+	if len(nodes) == 0 {
+		return nil, errors.New("no cluster nodes available for info command " + redactInfoCommand(command))
+	}
+
+	responses := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		res, err := c.requestInfo(ctx, infoPol, n, command)
+		if err != nil {
+			return nil, err
+		}
+		responses[n.GetName()] = res
+	}
+
+	return responses, nil
+}
+
+// infoAnyNode sends an info command to a single node, picked according to info_target, and returns its response.
+func (c *asConnection) infoAnyNode(ctx context.Context, command string) (string, error) {
+	return c.infoAnyNodeWithPolicy(ctx, c.infoPolicy, command)
+}
+
+// infoAnyNodeWithPolicy is infoAnyNode with a specific info policy.
+func (c *asConnection) infoAnyNodeWithPolicy(ctx context.Context, infoPol *as.InfoPolicy, command string) (string, error) {
+	if !c.supportsInfo() {
+		return "", errInfoNotSupported
+	}
+
+	if infoPol == nil {
+		infoPol = as.NewInfoPolicy()
+	}
+
+	nodes := c.getClient(ctx).GetNodes()
+	if len(nodes) == 0 {
+		return "", errors.New("no cluster nodes available for info command " + redactInfoCommand(command))
+	}
+
+	node := nodes[0]
+	switch c.infoTarget {
+	case infoTargetSeed:
+		node = seedNode(nodes, c.hosts)
+		if node == nil {
+			return "", fmt.Errorf("info command %s failed: the client isn't connected to a seed host, "+
+				"info_target = \"seed\" needs the node of a seed host", redactInfoCommand(command))
+		}
+	case infoTargetRandom:
+		node = nodes[rand.IntN(len(nodes))]
+	case infoTargetQuorum:
+		// commands that change the cluster are still sent once
+		if isReadInfoCommand(command) {
+			return c.infoQuorum(ctx, infoPol, nodes, command)
+		}
+	}
+
+	return c.requestInfo(ctx, infoPol, node, command)
+}
+
+// requestInfo sends an info command to node and returns its response.
+func (c *asConnection) requestInfo(ctx context.Context, infoPol *as.InfoPolicy, node *as.Node, command string) (string, error) {
+	res, err := retry(ctx, c.retryPolicy, func() (map[string]string, as.Error) { return node.RequestInfo(infoPol, command) })
+	if err != nil {
+		return "", fmt.Errorf("info command %s failed on node %s: %w", redactInfoCommand(command), node.GetName(), err)
+	}
+
+	c.logInfoResponse(ctx, node.GetName(), command, res[command])
+	c.auditInfo(ctx, node.GetName(), command, res[command])
+
+	return res[command], nil
+}
+
+// infoQuorum sends an info command to every node and returns the response of a majority of them. Nodes that fail
+// count against the majority.
+func (c *asConnection) infoQuorum(ctx context.Context, infoPol *as.InfoPolicy, nodes []*as.Node, command string) (string, error) {
+	responses := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		res, err := c.requestInfo(ctx, infoPol, n, command)
+		if err != nil {
+			tflog.Warn(ctx, err.Error())
+			continue
+		}
+		responses[n.GetName()] = res
+	}
+
+	response, ok := quorumResponse(responses, len(nodes))
+	if !ok {
+		return "", fmt.Errorf("info command %s failed: no response was returned by a majority of the %d nodes, "+
+			"the nodes may disagree while the cluster changes", redactInfoCommand(command), len(nodes))
+	}
+
+	return response, nil
+}
+
+// quorumResponse returns the response returned by more than half of the nodes.
+func quorumResponse(responses map[string]string, nodes int) (string, bool) {
+	counts := make(map[string]int, len(responses))
+	for _, res := range responses {
+		counts[res]++
+		if counts[res]*2 > nodes {
+			return res, true
+		}
+	}
+
+	return "", false
+}
+
+// seedNode returns the node of one of the seed hosts, or nil if the client isn't connected to any of them.
+func seedNode(nodes []*as.Node, seeds []*as.Host) *as.Node {
+	for _, seed := range seeds {
+		for _, n := range nodes {
+			if h := n.GetHost(); h != nil && strings.EqualFold(h.Name, seed.Name) && h.Port == seed.Port {
+				return n
+			}
+		}
+	}
+
+	return nil
+}
+
+// logInfoResponse logs the raw response of an info command when debug_info_responses is enabled.
+func (c *asConnection) logInfoResponse(ctx context.Context, node, command, response string) {
+	if !c.debugInfoResponses {
+		return
+	}
+
+	tflog.Info(ctx, "info command response", map[string]interface{}{
+		"node":     node,
+		"command":  redactInfoCommand(command),
+		"response": redactInfoParams(response),
+	})
+}
+
+// parseInfoParams parses a "key1=value1;key2=value2" info response into a map.
+func parseInfoParams(response string) map[string]string {
+	params := make(map[string]string)
+	for _, kv := range strings.Split(strings.TrimSpace(response), ";") {
+		if kv == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(kv, "=")
+		params[k] = v
+	}
+
+	return params
+}
+
+// configSelector builds the context part of a get-config/set-config command.
+func configSelector(context, namespace, dc string) string {
+	selector := "context=" + context
+	if dc != "" {
+		selector += ";dc=" + dc
+	}
+	if namespace != "" {
+		if context == "namespace" {
+			selector += ";id=" + namespace
+		} else {
+			selector += ";namespace=" + namespace
+		}
+	}
+
+	return selector
+}
+
+func getConfigCommand(context, namespace, dc string) string {
+	return "get-config:" + configSelector(context, namespace, dc)
+}
+
+func setConfigCommand(context, namespace, dc, param, value string) string {
+	return "set-config:" + configSelector(context, namespace, dc) + ";" + param + "=" + value
+}
+
+// setConfig sends a set-config command for every parameter to all nodes, then verifies the values with get-config.
+// Mismatches are reported on the attribute returned by paramPath for the parameter. The values the parameters had
+// before they were set are returned, as reported by one of the nodes.
+func (c *asConnection) setConfig(ctx context.Context, infoPol *as.InfoPolicy, configContext, namespace, dc string,
+	params map[string]string, paramPath func(string) path.Path) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	previous := make(map[string]string, len(params))
+	if len(params) == 0 {
+		return previous, diags
+	}
+
+	command := getConfigCommand(configContext, namespace, dc)
+	res, err := c.infoAnyNodeWithPolicy(ctx, infoPol, command)
+	if err != nil {
+		diags.AddError("Error reading configuration", err.Error())
+		return previous, diags
+	}
+	current := parseInfoParams(res)
+
+	for _, k := range sortedKeys(params) {
+		if v, ok := current[k]; ok {
+			previous[k] = v
+			tflog.Info(ctx, fmt.Sprintf("changing %s %s \u2192 %s", k, redactInfoValue(k, v), redactInfoValue(k, params[k])))
+		}
+		command := setConfigCommand(configContext, namespace, dc, k, params[k])
+		responses, err := c.infoAllNodesWithPolicy(ctx, infoPol, command)
+		if err != nil {
+			diags.AddError("Error setting configuration", err.Error())
+			return previous, diags
+		}
+		// the server answers a rejected set-config with an error message instead of failing the call
+		for _, node := range sortedKeys(responses) {
+			if msg, ok := infoErrorMessage(responses[node]); ok {
+				detail := fmt.Sprintf("Node %s rejected %s: %s", node, redactInfoCommand(command), msg)
+				if hint := c.configParamVersionError(ctx, configContext, k); hint != "" {
+					detail += ". " + hint
+				}
+				diags.AddAttributeError(paramPath(k), "Configuration rejected", detail)
+			}
+		}
+		if diags.HasError() {
+			return previous, diags
+		}
+		tflog.Trace(ctx, "sent "+redactInfoCommand(command))
+	}
+
+	responses, err := c.infoAllNodesWithPolicy(ctx, infoPol, command)
+	if err != nil {
+		diags.AddError("Error verifying configuration", err.Error())
+		return previous, diags
+	}
+
+	for _, node := range sortedKeys(responses) {
+		current := parseInfoParams(responses[node])
+		for _, k := range sortedKeys(params) {
+			if current[k] != params[k] {
+				diags.AddAttributeError(paramPath(k), "Configuration not applied",
+					fmt.Sprintf("Node %s reports %s=%q after setting it to %q. Check the parameter name and use the value format returned by get-config",
+						node, k, redactInfoValue(k, current[k]), redactInfoValue(k, params[k])))
+			}
+		}
+	}
+
+	return previous, diags
+}
+
+// configParamVersionError is unsupportedConfigParam for the connected cluster. The version check only explains
+// errors, so a failure to read the version is logged and "" returned.
+func (c *asConnection) configParamVersionError(ctx context.Context, configContext, param string) string {
+	if _, ok := configParamVersions[configContext][param]; !ok {
+		return ""
+	}
+
+	version, err := c.serverVersion(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "unable to read the server version: "+err.Error())
+		return ""
+	}
+
+	return unsupportedConfigParam(configContext, param, version)
+}
+
+// sensitiveInfoParams are the name fragments of info command parameters that carry credentials.
+var sensitiveInfoParams = []string{"password", "secret", "token", "credential"}
+
+// redactedValue replaces the values of sensitive parameters in logs and messages.
+const redactedValue = "<redacted>"
+
+// redactInfoValue returns the value, or redactedValue if the parameter carries credentials.
+func redactInfoValue(param, value string) string {
+	name := strings.ToLower(param)
+	for _, s := range sensitiveInfoParams {
+		if strings.Contains(name, s) && value != "" {
+			return redactedValue
+		}
+	}
+
+	return value
+}
+
+// redactInfoParams masks the sensitive values of a "key1=value1;key2=value2" string.
+func redactInfoParams(params string) string {
+	parts := strings.Split(params, ";")
+	for i, kv := range parts {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			parts[i] = k + "=" + redactInfoValue(k, v)
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// redactInfoCommand masks the sensitive parameter values of an info command so it can be logged or shown in errors.
+func redactInfoCommand(command string) string {
+	name, params, ok := strings.Cut(command, ":")
+	if !ok {
+		return command
+	}
+
+	return name + ":" + redactInfoParams(params)
+}
+
+// sortedKeys returns the keys of a map in a stable order so commands are always sent in the same sequence.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// serverVersion returns the version reported by the build info command, e.g. "7.1.0.2".
+func (c *asConnection) serverVersion(ctx context.Context) (string, error) {
+	res, err := c.infoAnyNode(ctx, "build")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(res), nil
+}
+
+// versionAtLeast reports whether the dotted version is at least minVersion. Missing or non numeric parts count as 0.
+func versionAtLeast(version, minVersion string) bool {
+	v := strings.Split(version, ".")
+	m := strings.Split(minVersion, ".")
+
+	for i := 0; i < len(v) || i < len(m); i++ {
+		var vPart, mPart int
+		if i < len(v) {
+			vPart, _ = strconv.Atoi(v[i])
+		}
+		if i < len(m) {
+			mPart, _ = strconv.Atoi(m[i])
+		}
+		if vPart != mPart {
+			return vPart > mPart
+		}
+	}
+
+	return true
+}
+
+// configParamVersions are the server versions that added or removed configuration parameters, keyed by context and
+// parameter name. Parameters that aren't listed are left for the server to check.
+var configParamVersions = map[string]map[string]struct{ added, removed string }{
+	"namespace": {
+		"default-read-touch-ttl-pct": {added: "7.1"},
+		"evict-indexes-memory-pct":   {added: "7.0"},
+		"evict-sys-memory-pct":       {added: "7.0"},
+		"indexes-memory-budget":      {added: "7.0"},
+		"max-record-size":            {added: "7.0"},
+		"sindex-stage-size":          {added: "7.0"},
+		"high-water-disk-pct":        {removed: "7.0"},
+		"high-water-memory-pct":      {removed: "7.0"},
+		"memory-size":                {removed: "7.0"},
+		"stop-writes-pct":            {removed: "7.0"},
+	},
+}
+
+// unsupportedConfigParam returns an error message if the server version doesn't have the configuration parameter,
+// or "" if it does or the parameter isn't listed in configParamVersions.
+func unsupportedConfigParam(configContext, param, version string) string {
+	v, ok := configParamVersions[configContext][param]
+	if !ok {
+		return ""
+	}
+	if v.added != "" && !versionAtLeast(version, v.added) {
+		return fmt.Sprintf("%s requires server version %s or later, the cluster runs %s", param, v.added, version)
+	}
+	if v.removed != "" && versionAtLeast(version, v.removed) {
+		return fmt.Sprintf("%s was removed in server version %s, the cluster runs %s", param, v.removed, version)
+	}
+
+	return ""
+}
+
+// namespaces returns the namespaces of the cluster, sorted.
+func (c *asConnection) namespaces(ctx context.Context) ([]string, error) {
+	res, err := c.infoAnyNode(ctx, "namespaces")
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0)
+	for _, ns := range strings.Split(strings.TrimSpace(res), ";") {
+		if ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, nil
+}
+
+// namespaceExists reports whether the namespace is listed by the namespaces info command.
+func (c *asConnection) namespaceExists(ctx context.Context, namespace string) (bool, error) {
+	namespaces, err := c.namespaces(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// checkNamespace reports an error on attrPath, naming the available namespaces, if the cluster doesn't have the
+// namespace. The check is skipped with a warning if the namespaces can't be read.
+func (c *asConnection) checkNamespace(ctx context.Context, attrPath path.Path, namespace string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	namespaces, err := c.namespaces(ctx)
+	if err != nil {
+		diags.AddWarning("Unable to check the namespace", err.Error())
+		return diags
+	}
+
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return diags
+		}
+	}
+	diags.AddAttributeError(attrPath, "Unknown namespace",
+		fmt.Sprintf("Namespace %s does not exist in the cluster. Available namespaces: %s", namespace, strings.Join(namespaces, ", ")))
+
+	return diags
+}
+
+// quotasEnabled reports whether enable-quotas is set in the security context.
+func (c *asConnection) quotasEnabled(ctx context.Context) (bool, error) {
+	res, err := c.infoAnyNode(ctx, getConfigCommand("security", "", ""))
+	if err != nil {
+		return false, err
+	}
+
+	return parseInfoParams(res)["enable-quotas"] == "true", nil
+}
+
+// isInfoError reports whether an info response is an error message rather than a value.
+func isInfoError(response string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(response)), "error")
+}
+
+// infoErrorMessage returns the message of an error response such as "ERROR:4:invalid parameter" or
+// "error::unknown namespace", and whether the response is an error. Responses without a message are returned as is.
+func infoErrorMessage(response string) (string, bool) {
+	if !isInfoError(response) {
+		return "", false
+	}
+
+	response = strings.TrimSpace(response)
+	parts := strings.SplitN(response, ":", 3)
+	if len(parts) < 3 || strings.TrimSpace(parts[2]) == "" {
+		return response, true
+	}
+	msg := strings.TrimSpace(parts[2])
+	if parts[1] != "" {
+		msg += " (error code " + parts[1] + ")"
+	}
+
+	return msg, true
+}
+
+// clusterFeatures describes the edition of the cluster and the enterprise features it has enabled.
+type clusterFeatures struct {
+	edition                     string
+	version                     string
+	securityEnabled             bool
+	quotasEnabled               bool
+	xdrEnabled                  bool
+	strongConsistencyLicensed   bool
+	strongConsistencyNamespaces []string
+}
+
+// readClusterFeatures detects the edition of the cluster and its enabled features. Community Edition clusters
+// report every enterprise feature as disabled.
+func (c *asConnection) readClusterFeatures(ctx context.Context) (clusterFeatures, error) {
+	var features clusterFeatures
+
+	edition, err := c.infoAnyNode(ctx, "edition")
+	if err != nil {
+		return features, err
+	}
+	features.edition = "community"
+	if strings.Contains(strings.ToLower(edition), "enterprise") {
+		features.edition = "enterprise"
+	}
+
+	features.version, err = c.serverVersion(ctx)
+	if err != nil {
+		return features, err
+	}
+
+	if features.edition != "enterprise" {
+		return features, nil
+	}
+
+	// the security context only exists when security is enabled
+	res, err := c.infoAnyNode(ctx, getConfigCommand("security", "", ""))
+	if err != nil {
+		return features, err
+	}
+	if !isInfoError(res) && strings.TrimSpace(res) != "" {
+		features.securityEnabled = true
+		features.quotasEnabled = parseInfoParams(res)["enable-quotas"] == "true"
+	}
+
+	res, err = c.infoAnyNode(ctx, getConfigCommand("xdr", "", ""))
+	if err != nil {
+		return features, err
+	}
+	features.xdrEnabled = !isInfoError(res) && parseInfoParams(res)["dcs"] != ""
+
+	res, err = c.infoAnyNode(ctx, "feature-key")
+	if err != nil {
+		return features, err
+	}
+	features.strongConsistencyLicensed = parseInfoParams(res)["asdb-strong-consistency"] == "true"
+
+	res, err = c.infoAnyNode(ctx, "namespaces")
+	if err != nil {
+		return features, err
+	}
+	features.strongConsistencyNamespaces = make([]string, 0)
+	for _, ns := range strings.Split(strings.TrimSpace(res), ";") {
+		if ns == "" {
+			continue
+		}
+		nsConfig, err := c.infoAnyNode(ctx, getConfigCommand("namespace", ns, ""))
+		if err != nil {
+			return features, err
+		}
+		if parseInfoParams(nsConfig)["strong-consistency"] == "true" {
+			features.strongConsistencyNamespaces = append(features.strongConsistencyNamespaces, ns)
+		}
+	}
+	sort.Strings(features.strongConsistencyNamespaces)
+
+	return features, nil
+}
+
+// clusterStablePollInterval is the default time between cluster-stable checks while waiting.
+const clusterStablePollInterval = 2 * time.Second
+
+func clusterStableCommand(size int64, namespace string, ignoreMigrations bool) string {
+	params := []string{"ignore-migrations=" + strconv.FormatBool(ignoreMigrations)}
+	if size > 0 {
+		params = append(params, "size="+strconv.FormatInt(size, 10))
+	}
+	if namespace != "" {
+		params = append(params, "namespace="+namespace)
+	}
+
+	return "cluster-stable:" + strings.Join(params, ";")
+}
+
+// clusterStableKey returns the cluster key the nodes agree on, or the reason the cluster isn't stable.
+func clusterStableKey(responses map[string]string) (string, string) {
+	key := ""
+	for _, node := range sortedKeys(responses) {
+		res := strings.TrimSpace(responses[node])
+		if isInfoError(res) {
+			return "", "node " + node + " returned " + res
+		}
+		if key != "" && res != key {
+			return "", "nodes report different cluster keys " + key + " and " + res
+		}
+		key = res
+	}
+
+	if key == "" {
+		return "", "no cluster key reported"
+	}
+
+	return key, ""
+}
+
+// migrationsRemaining returns the number of partitions left to migrate, summed over all nodes.
+func (c *asConnection) migrationsRemaining(ctx context.Context, infoPol *as.InfoPolicy) (int64, error) {
+	responses, err := c.infoAllNodesWithPolicy(ctx, infoPol, "statistics")
+	if err != nil {
+		return 0, err
+	}
+
+	var remaining int64
+	for _, res := range responses {
+		n, _ := strconv.ParseInt(parseInfoParams(res)["migrate_partitions_remaining"], 10, 64)
+		remaining += n
+	}
+
+	return remaining, nil
+}
+
+// waitForMigrations polls cluster-stable until all the nodes agree on the cluster key and no migrations are left,
+// logging the remaining partitions while waiting.
+func (c *asConnection) waitForMigrations(ctx context.Context, infoPol *as.InfoPolicy, timeout, pollInterval time.Duration) error {
+	if err := c.waitForClusterStable(ctx, infoPol, false, timeout, pollInterval); err != nil {
+		return fmt.Errorf("migrations did not complete within %s: %w", timeout, err)
+	}
+
+	return nil
+}
+
+// waitForClusterStable polls cluster-stable until all the nodes agree on the cluster key, and no migrations are left
+// unless ignoreMigrations is set, checking every pollInterval. The error is the last reason the cluster wasn't stable.
+func (c *asConnection) waitForClusterStable(ctx context.Context, infoPol *as.InfoPolicy, ignoreMigrations bool, timeout, pollInterval time.Duration) error {
+	command := clusterStableCommand(0, "", ignoreMigrations)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var reason string
+		responses, err := c.infoAllNodesWithPolicy(ctx, infoPol, command)
+		if err != nil {
+			reason = err.Error()
+		} else {
+			_, reason = clusterStableKey(responses)
+		}
+		if reason == "" {
+			return nil
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return errors.New(reason)
+		}
+
+		if ignoreMigrations {
+			tflog.Info(ctx, "waiting for the cluster to form: "+reason)
+		} else if remaining, err := c.migrationsRemaining(ctx, infoPol); err == nil {
+			tflog.Info(ctx, fmt.Sprintf("waiting for migrations, %d partitions remaining", remaining))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
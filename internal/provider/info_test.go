@@ -0,0 +1,258 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+)
+
+func TestConfigCommands(t *testing.T) {
+	cases := []struct {
+		context, namespace, dc, get, set string
+	}{
+		{"service", "", "", "get-config:context=service", "set-config:context=service;p=v"},
+		{"network", "", "", "get-config:context=network", "set-config:context=network;p=v"},
+		{"namespace", "test", "", "get-config:context=namespace;id=test", "set-config:context=namespace;id=test;p=v"},
+		{"xdr", "", "dc1", "get-config:context=xdr;dc=dc1", "set-config:context=xdr;dc=dc1;p=v"},
+		{"xdr", "test", "dc1", "get-config:context=xdr;dc=dc1;namespace=test", "set-config:context=xdr;dc=dc1;namespace=test;p=v"},
+	}
+
+	for _, c := range cases {
+		if got := getConfigCommand(c.context, c.namespace, c.dc); got != c.get {
+			t.Errorf("getConfigCommand(%q, %q, %q) = %q, want %q", c.context, c.namespace, c.dc, got, c.get)
+		}
+		if got := setConfigCommand(c.context, c.namespace, c.dc, "p", "v"); got != c.set {
+			t.Errorf("setConfigCommand(%q, %q, %q) = %q, want %q", c.context, c.namespace, c.dc, got, c.set)
+		}
+	}
+}
+
+func TestParseInfoParams(t *testing.T) {
+	got := parseInfoParams("enable-quotas=true;privilege-refresh-period=300;empty=;\n")
+	want := map[string]string{
+		"enable-quotas":            "true",
+		"privilege-refresh-period": "300",
+		"empty":                    "",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseInfoParams() = %v, want %v", got, want)
+	}
+}
+
+func TestInfoNotSupportedWithProxy(t *testing.T) {
+	conn := newFakeConnection(newFakeClient(), "admin")
+	conn.clientType = as.CTProxy
+
+	if _, err := conn.infoAnyNode(context.Background(), "build"); err != errInfoNotSupported {
+		t.Errorf("infoAnyNode() error = %v, want %v", err, errInfoNotSupported)
+	}
+	if _, err := conn.infoAllNodes(context.Background(), "build"); err != errInfoNotSupported {
+		t.Errorf("infoAllNodes() error = %v, want %v", err, errInfoNotSupported)
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	cases := []struct {
+		version, minVersion string
+		want                bool
+	}{
+		{"7.1.0.2", "6.0", true},
+		{"6.0.0.0", "6.0", true},
+		{"5.7.0.17", "6.0", false},
+		{"6", "6.0.0.1", false},
+		{"10.0", "6.0", true},
+	}
+
+	for _, c := range cases {
+		if got := versionAtLeast(c.version, c.minVersion); got != c.want {
+			t.Errorf("versionAtLeast(%q, %q) = %v, want %v", c.version, c.minVersion, got, c.want)
+		}
+	}
+}
+
+func TestUnsupportedConfigParam(t *testing.T) {
+	cases := []struct {
+		context, param, version, want string
+	}{
+		{"namespace", "max-record-size", "6.4.0.1", "max-record-size requires server version 7.0 or later, the cluster runs 6.4.0.1"},
+		{"namespace", "max-record-size", "7.0.0.3", ""},
+		{"namespace", "memory-size", "7.1.0.0", "memory-size was removed in server version 7.0, the cluster runs 7.1.0.0"},
+		{"namespace", "memory-size", "6.4.0.1", ""},
+		{"service", "memory-size", "7.1.0.0", ""},
+		{"namespace", "default-ttl", "7.1.0.0", ""},
+	}
+
+	for _, c := range cases {
+		if got := unsupportedConfigParam(c.context, c.param, c.version); got != c.want {
+			t.Errorf("unsupportedConfigParam(%q, %q, %q) = %q, want %q", c.context, c.param, c.version, got, c.want)
+		}
+	}
+}
+
+func TestRedactInfoCommand(t *testing.T) {
+	cases := []struct {
+		command, want string
+	}{
+		{"build", "build"},
+		{"get-config:context=service", "get-config:context=service"},
+		{"set-config:context=xdr;dc=dc1;auth-password=secret1", "set-config:context=xdr;dc=dc1;auth-password=<redacted>"},
+		{"set-config:context=xdr;dc=dc1;Auth-Token=abc;period-ms=100", "set-config:context=xdr;dc=dc1;Auth-Token=<redacted>;period-ms=100"},
+		{"set-config:context=xdr;dc=dc1;auth-password=", "set-config:context=xdr;dc=dc1;auth-password="},
+	}
+
+	for _, c := range cases {
+		if got := redactInfoCommand(c.command); got != c.want {
+			t.Errorf("redactInfoCommand(%q) = %q, want %q", c.command, got, c.want)
+		}
+	}
+}
+
+func TestInfoErrorMessage(t *testing.T) {
+	cases := []struct {
+		response, want string
+		isError        bool
+	}{
+		{"ok", "", false},
+		{"migrate-threads=4;proto-fd-max=15000", "", false},
+		{"ERROR:4:invalid parameter value\n", "invalid parameter value (error code 4)", true},
+		{"error::unknown namespace", "unknown namespace", true},
+		{"error", "error", true},
+		{"ERROR::", "ERROR::", true},
+	}
+
+	for _, c := range cases {
+		got, isError := infoErrorMessage(c.response)
+		if got != c.want || isError != c.isError {
+			t.Errorf("infoErrorMessage(%q) = %q, %v, want %q, %v", c.response, got, isError, c.want, c.isError)
+		}
+	}
+}
+
+func TestWaitForNodes(t *testing.T) {
+	client := newFakeClient()
+
+	if err := waitForNodes(context.Background(), client, 0, time.Now()); err != nil {
+		t.Errorf("waitForNodes() with enough nodes error = %v, want nil", err)
+	}
+	if err := waitForNodes(context.Background(), client, 3, time.Now()); err == nil {
+		t.Error("waitForNodes() past the deadline error = nil, want an error")
+	}
+}
+
+// expiredSessionClient is a fake client whose session expired.
+type expiredSessionClient struct {
+	*fakeClient
+}
+
+func (c *expiredSessionClient) QueryUsers(policy *as.AdminPolicy) ([]*as.UserRoles, as.Error) {
+	return nil, fakeError(astypes.EXPIRED_SESSION)
+}
+
+func TestReloginClientRetries(t *testing.T) {
+	// another goroutine already logged in again, so the retry uses its client without reconnecting
+	current := newFakeClient()
+	current.users["u1"] = &as.UserRoles{User: "u1"}
+	conn := newFakeConnection(current, "admin")
+	expired := &reloginClient{ctx: context.Background(), conn: conn, client: &expiredSessionClient{newFakeClient()}}
+
+	users, err := expired.QueryUsers(conn.adminPolicy)
+	if err != nil {
+		t.Fatalf("QueryUsers() error = %v, want nil", err)
+	}
+	if len(users) != 1 || users[0].User != "u1" {
+		t.Errorf("QueryUsers() = %v, want the users of the current client", users)
+	}
+	if !isSessionError(fakeError(astypes.NOT_AUTHENTICATED)) || isSessionError(fakeError(astypes.INVALID_USER)) {
+		t.Error("isSessionError() doesn't match the session result codes")
+	}
+}
+
+// barrierClient is a fake client whose CreateUser signals arrived when it's called and returns once release is closed.
+type barrierClient struct {
+	*fakeClient
+	mutex   sync.Mutex
+	arrived chan struct{}
+	release chan struct{}
+}
+
+func (c *barrierClient) CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) as.Error {
+	c.arrived <- struct{}{}
+	<-c.release
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.fakeClient.CreateUser(policy, user, password, roles)
+}
+
+func TestConcurrentAdminCommands(t *testing.T) {
+	// terraform creates resources in parallel, the connection must not run their admin commands one at a time
+	const parties = 10
+	client := &barrierClient{fakeClient: newFakeClient(), arrived: make(chan struct{}), release: make(chan struct{})}
+	conn := newFakeConnection(client.fakeClient, "admin")
+	conn.client = client
+
+	var wg sync.WaitGroup
+	errs := make(chan as.Error, parties)
+	for i := 0; i < parties; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- conn.getClient(context.Background()).CreateUser(conn.adminPolicy, fmt.Sprintf("user%d", i), "password", nil)
+		}()
+	}
+
+	for i := 0; i < parties; i++ {
+		select {
+		case <-client.arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d CreateUser calls ran at the same time", i, parties)
+		}
+	}
+	close(client.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CreateUser() = %v, want nil", err)
+		}
+	}
+	if len(client.users) != parties {
+		t.Errorf("created %d users, want %d", len(client.users), parties)
+	}
+}
+
+func TestQuorumResponse(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]string
+		nodes     int
+		want      string
+		ok        bool
+	}{
+		{"all agree", map[string]string{"A": "x", "B": "x", "C": "x"}, 3, "x", true},
+		{"majority", map[string]string{"A": "x", "B": "y", "C": "x"}, 3, "x", true},
+		{"failed node counts against", map[string]string{"A": "x", "B": "y"}, 3, "", false},
+		{"even split", map[string]string{"A": "x", "B": "y"}, 2, "", false},
+		{"single node", map[string]string{"A": "x"}, 1, "x", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := quorumResponse(tt.responses, tt.nodes)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("quorumResponse() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -4,28 +4,37 @@
 package provider
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"fmt"
 	as "github.com/aerospike/aerospike-client-go/v7"
 	astypes "github.com/aerospike/aerospike-client-go/v7/types"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-	"io"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"net"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Ensure AerospikeProvider satisfies various provider interfaces.
 var _ provider.Provider = &AerospikeProvider{}
+var _ provider.ProviderWithEphemeralResources = &AerospikeProvider{}
+var _ provider.ProviderWithValidateConfig = &AerospikeProvider{}
 
 // AerospikeProvider defines the provider implementation.
 type AerospikeProvider struct {
@@ -37,12 +46,34 @@ type AerospikeProvider struct {
 
 // AerospikeProviderModel describes the provider data model.
 type AerospikeProviderModel struct {
-	Host            types.String `tfsdk:"host"`
-	Port            types.Int64  `tfsdk:"port"`
-	User_name       types.String `tfsdk:"user_name"`
-	Password        types.String `tfsdk:"password"`
-	Connect_timeout types.Int64  `tfsdk:"connect_timeout"`
-	TLS             types.Object `tfsdk:"tls"`
+	Host                  types.String  `tfsdk:"host"`
+	Port                  types.Int64   `tfsdk:"port"`
+	User_name             types.String  `tfsdk:"user_name"`
+	Password              types.String  `tfsdk:"password"`
+	Password_file         types.String  `tfsdk:"password_file"`
+	Password_command      types.String  `tfsdk:"password_command"`
+	Auth_mode             types.String  `tfsdk:"auth_mode"`
+	Connect_timeout       types.Int64   `tfsdk:"connect_timeout"`
+	Connection_pool_size  types.Int64   `tfsdk:"connection_pool_size"`
+	Max_error_rate        types.Int64   `tfsdk:"max_error_rate"`
+	Error_rate_window     types.Int64   `tfsdk:"error_rate_window"`
+	Info_timeout          types.Int64   `tfsdk:"info_timeout"`
+	Info_target           types.String  `tfsdk:"info_target"`
+	Config_file           types.String  `tfsdk:"config_file"`
+	Config_instance       types.String  `tfsdk:"config_instance"`
+	Debug_info_responses  types.Bool    `tfsdk:"debug_info_responses"`
+	Allow_destructive     types.Bool    `tfsdk:"allow_destructive_operations"`
+	Read_only             types.Bool    `tfsdk:"read_only"`
+	Audit_log_file        types.String  `tfsdk:"audit_log_file"`
+	Retry_policy          types.Object  `tfsdk:"retry_policy"`
+	Fail_if_not_connected types.Bool    `tfsdk:"fail_if_not_connected"`
+	Min_connected_nodes   types.Int64   `tfsdk:"min_connected_nodes"`
+	Client_type           types.String  `tfsdk:"client_type"`
+	TLS                   types.Object  `tfsdk:"tls"`
+	Wait_for_cluster      types.Object  `tfsdk:"wait_for_cluster"`
+	Rack_aware            types.Bool    `tfsdk:"rack_aware"`
+	Rack_ids              []types.Int64 `tfsdk:"rack_ids"`
+	Host_srv_record       types.String  `tfsdk:"host_srv_record"`
 }
 
 type AerospikeTLSConfigModel struct {
@@ -50,8 +81,55 @@ type AerospikeTLSConfigModel struct {
 	RootCAFile types.String `tfsdk:"root_ca_file"`
 }
 
+type AerospikeWaitForClusterModel struct {
+	Expected_nodes types.Int64  `tfsdk:"expected_nodes"`
+	Timeout        types.String `tfsdk:"timeout"`
+}
+
+// auth_mode values.
+const (
+	authModeInternal = "internal"
+	authModeExternal = "external"
+)
+
+// defaultWaitForClusterTimeout is used when wait_for_cluster doesn't set a timeout.
+const defaultWaitForClusterTimeout = 2 * time.Minute
+
+// asConnection is shared by all resources and data sources, which terraform runs concurrently. The client is
+// replaced on reconnect, so it must only be accessed through getClient.
 type asConnection struct {
-	client *as.ClientIfc
+	// mutex guards client
+	mutex  sync.RWMutex
+	client aerospikeClient
+	// clientType, clientPolicy and hosts are kept to re-create the client if the connection is lost
+	clientType   as.ClientType
+	clientPolicy *as.ClientPolicy
+	hosts        []*as.Host
+	// adminPolicy and infoPolicy are shared by all resources. Policies are only read by the client so concurrent use is safe
+	adminPolicy *as.AdminPolicy
+	infoPolicy  *as.InfoPolicy
+	// readPolicy prefers the nodes of the client's racks when rack_aware is set
+	readPolicy *as.BasePolicy
+	// userName is the user the provider authenticates with
+	userName string
+	// debugInfoResponses logs the raw response of every info command
+	debugInfoResponses bool
+	// blockDestructive is set when allow_destructive_operations is false
+	blockDestructive bool
+	// readOnly fails every create, update and delete
+	readOnly bool
+	// auditLog records the commands that change the cluster when audit_log_file is set
+	auditLog *auditLog
+	// infoTarget picks the node of info commands sent to a single node, one of the infoTarget constants
+	infoTarget string
+	// retryPolicy retries failed commands when retry_policy is set
+	retryPolicy *retryPolicy
+	// enterprise and securityEnabled are detected when the provider is configured
+	enterprise      bool
+	securityEnabled bool
+	// overrides caches the connections of resources with a connection block, keyed by host, port and user
+	overridesMutex sync.Mutex
+	overrides      map[string]*asConnection
 }
 
 func (p *AerospikeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -63,9 +141,17 @@ func (p *AerospikeProvider) Schema(ctx context.Context, req provider.SchemaReque
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "Seed host to connect to. Defaults to the environment variable AEROSPIKE_HOST",
+				Description: "Seed host to connect to. May include the port, e.g. db1:3000 or [::1]:3000. Defaults to the environment variable AEROSPIKE_HOST",
 				Optional:    true,
 			},
+			"host_srv_record": schema.StringAttribute{
+				Description: "DNS SRV record, e.g. _aerospike._tcp.aerospike.example.com, to resolve the seed hosts and ports from when the provider is configured. " +
+					"Conflicts with host and port, and takes precedence over AEROSPIKE_HOST and AEROSPIKE_PORT",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("host"), path.MatchRoot("port")),
+				},
+			},
 			"port": schema.Int64Attribute{
 				Description: "Port to connect to. Defaults to the environment variable AEROSPIKE_PORT",
 				Optional:    true,
@@ -82,6 +168,28 @@ func (p *AerospikeProvider) Schema(ctx context.Context, req provider.SchemaReque
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"password_file": schema.StringAttribute{
+				Description: "File to read the admin password from. Trailing newlines are removed",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("password"), path.MatchRoot("password_command")),
+				},
+			},
+			"password_command": schema.StringAttribute{
+				Description: "Command whose output is used as the admin password. The command is run directly, not through a shell. Trailing newlines are removed",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("password"), path.MatchRoot("password_file")),
+				},
+			},
+			"auth_mode": schema.StringAttribute{
+				Description: "How the user authenticates. internal uses users defined in the cluster, external uses an external " +
+					"service such as LDAP and requires tls. Defaults to the environment variable AEROSPIKE_AUTH_MODE or internal",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(authModeInternal, authModeExternal),
+				},
+			},
 			"connect_timeout": schema.Int64Attribute{
 				Description: "Connect timeout. Defaults to the environment variable AEROSPIKE_CONNECT_TIMEOUT. Range is 1-60 seconds",
 				Optional:    true,
@@ -89,14 +197,177 @@ func (p *AerospikeProvider) Schema(ctx context.Context, req provider.SchemaReque
 					int64validator.Between(0, 60),
 				},
 			},
+			"connection_pool_size": schema.Int64Attribute{
+				Description: "Maximum number of connections per node. Raise it together with terraform's -parallelism " +
+					"when applying many users or roles at once. Defaults to the client default of 100",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_error_rate": schema.Int64Attribute{
+				Description: "Maximum number of errors per node within error_rate_window before the client stops sending it commands " +
+					"until the window ends. 0 disables the circuit breaker. Raise it if large applies fail with MAX_ERROR_RATE. " +
+					"Defaults to the client default of 100",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"error_rate_window": schema.Int64Attribute{
+				Description: "Number of cluster tend iterations (1 second each) over which max_error_rate is counted. " +
+					"Defaults to the client default of 1",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"info_timeout": schema.Int64Attribute{
+				Description: "Timeout in seconds for info commands such as set-config and get-config. Raise it for busy clusters. " +
+					"Defaults to the environment variable AEROSPIKE_INFO_TIMEOUT or the client default of 1 second",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 600),
+				},
+			},
+			"info_target": schema.StringAttribute{
+				Description: "Which node info commands that read a single node, e.g. get-config, are sent to. any uses the first node " +
+					"the client lists, seed the node of a seed host and fails if the client isn't connected to one, random a random node, " +
+					"and quorum sends the command to every node and uses the response of a majority of them. With quorum, commands that " +
+					"change the cluster are still sent to a single node. Defaults to any",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(infoTargetAny, infoTargetSeed, infoTargetRandom, infoTargetQuorum),
+				},
+			},
+			"config_file": schema.StringAttribute{
+				Description: "Aerospike tools configuration file (e.g. ~/.aerospike/astools.conf) to read the host, port, credentials and TLS settings from. " +
+					"Values set in the provider block or environment variables take precedence. Passwords given as env:, env-b64:, b64: or file: are resolved. Defaults to the environment variable AEROSPIKE_CONFIG_FILE",
+				Optional: true,
+			},
+			"config_instance": schema.StringAttribute{
+				Description: "Instance in config_file to use. The [cluster_<instance>] section is read instead of [cluster] when set",
+				Optional:    true,
+			},
+			"debug_info_responses": schema.BoolAttribute{
+				Description: "Log the raw response of every info command (set-config, get-config, ...) at INFO level. " +
+					"Useful for troubleshooting parameters the server accepts but doesn't apply",
+				Optional: true,
+			},
+			"allow_destructive_operations": schema.BoolAttribute{
+				Description: "Allow dropping users and roles and running truncate and UDF or index removal info commands. " +
+					"Set to false to guard production workspaces, the operations then fail with an error. Defaults to true",
+				Optional: true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "Fail every create, update and delete while plans, refreshes and data sources keep working. " +
+					"For audit-only workspaces and for running plans with production credentials. Defaults to false",
+				Optional: true,
+			},
+			"audit_log_file": schema.StringAttribute{
+				Description: "File to append an audit trail of the commands that change the cluster to, as JSON lines with the time, " +
+					"provider user, node, command and result. Admin commands have no node. Passwords and credential parameters are not recorded",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"client_type": schema.StringAttribute{
+				Description: "Client to connect with. native connects to the cluster nodes directly. proxy connects through the " +
+					"Aerospike proxy used by Aerospike Cloud, with user_name and password set to the API key ID and secret. " +
+					"proxy requires tls and doesn't support info commands, so aerospike_config and quota checks are unavailable. " +
+					"proxy is only available in provider binaries built with the as_proxy build tag, as the released ones are. Defaults to native",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("native", "proxy"),
+				},
+			},
 			"tls": schema.SingleNestedAttribute{
+				Description: "Connect with TLS. Also enabled when the environment variable AEROSPIKE_TLS_NAME or AEROSPIKE_ROOT_CA_FILE is set",
 				Attributes: map[string]schema.Attribute{
 					"tls_name": schema.StringAttribute{
-						Description: "tls name to use",
+						Description: "tls name to use. Defaults to the environment variable AEROSPIKE_TLS_NAME",
 						Optional:    true,
 					},
 					"root_ca_file": schema.StringAttribute{
-						Description: "root CA tls certificate file",
+						Description: "root CA tls certificate file, a PEM bundle. A leading ~ is expanded to the home directory and relative paths are relative to the Terraform working directory. " +
+							"Defaults to the environment variable AEROSPIKE_ROOT_CA_FILE",
+						Optional: true,
+					},
+				},
+				Optional: true,
+			},
+			"rack_aware": schema.BoolAttribute{
+				Description: "Prefer the nodes in rack_ids when reading, e.g. the nodes in the same availability zone as terraform",
+				Optional:    true,
+			},
+			"rack_ids": schema.ListAttribute{
+				Description: "Racks to prefer when rack_aware is set, in order of preference",
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Validators: []validator.List{
+					listvalidator.AlsoRequires(path.MatchRoot("rack_aware")),
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueInt64sAre(int64validator.AtLeast(0)),
+				},
+			},
+			"retry_policy": schema.SingleNestedAttribute{
+				Description: "Retry admin, info and record commands that fail with a transient error, for all resources and data sources. " +
+					"The wait before each retry doubles from base_backoff up to max_backoff. Commands aren't retried when not set",
+				Attributes: map[string]schema.Attribute{
+					"max_retries": schema.Int64Attribute{
+						Description: "Number of times a failed command is retried",
+						Required:    true,
+						Validators: []validator.Int64{
+							int64validator.Between(0, 10),
+						},
+					},
+					"base_backoff": schema.StringAttribute{
+						Description: "Wait before the first retry, as a duration such as \"100ms\". Defaults to 100ms",
+						Optional:    true,
+					},
+					"max_backoff": schema.StringAttribute{
+						Description: "Longest wait between retries, as a duration such as \"5s\". Defaults to 5s",
+						Optional:    true,
+					},
+					"retryable_errors": schema.ListAttribute{
+						Description: "Error classes to retry: timeout, network (connection failures and unavailable nodes), " +
+							"busy (device overload and hot keys) and quota (quota exceeded). Defaults to [\"timeout\", \"network\"]",
+						Optional:    true,
+						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.ValueStringsAre(stringvalidator.OneOf("timeout", "network", "busy", "quota")),
+						},
+					},
+				},
+				Optional: true,
+			},
+			"fail_if_not_connected": schema.BoolAttribute{
+				Description: "Fail when the provider can't connect to the cluster. When false, the provider is configured anyway and " +
+					"the client keeps connecting in the background, so plans that don't read the cluster still work. Defaults to true",
+				Optional: true,
+			},
+			"min_connected_nodes": schema.Int64Attribute{
+				Description: "Fail when the provider is connected to fewer cluster nodes, e.g. so applies don't run while nodes are down. " +
+					"Cluster nodes the provider can't reach are reported as warnings either way. Not supported with client_type = \"proxy\"",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"wait_for_cluster": schema.SingleNestedAttribute{
+				Description: "Wait for the cluster to form before using it, e.g. right after provisioning the nodes. " +
+					"Connection failures are retried and the provider waits until it sees expected_nodes nodes. Not supported with client_type = \"proxy\"",
+				Attributes: map[string]schema.Attribute{
+					"expected_nodes": schema.Int64Attribute{
+						Description: "Number of nodes the cluster must have",
+						Required:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"timeout": schema.StringAttribute{
+						Description: "How long to wait, as a duration such as \"2m\" or \"90s\". Defaults to 2m",
 						Optional:    true,
 					},
 				},
@@ -106,6 +377,33 @@ func (p *AerospikeProvider) Schema(ctx context.Context, req provider.SchemaReque
 	}
 }
 
+func (p *AerospikeProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data AerospikeProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unknown values and the tools config file are only resolved when the provider is configured, check them then.
+	// Missing hosts and ports are reported then too, they may be set by the environment of the apply.
+	for _, v := range []attr.Value{data.User_name, data.Password, data.Password_file, data.Password_command, data.Config_file} {
+		if v.IsUnknown() {
+			return
+		}
+	}
+	if withEnvironmentOverrideString(data.Config_file.ValueString(), "AEROSPIKE_CONFIG_FILE") != "" {
+		return
+	}
+
+	user := withEnvironmentOverrideString(data.User_name.ValueString(), "AEROSPIKE_USER")
+	// password_file and password_command are read when the provider is configured
+	passwordSet := withEnvironmentOverrideString(data.Password.ValueString(), "AEROSPIKE_PASSWORD") != "" ||
+		!data.Password_file.IsNull() || !data.Password_command.IsNull()
+	resp.Diagnostics.Append(missingCredentials(user, passwordSet)...)
+}
+
 func (p *AerospikeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data AerospikeProviderModel
 	var dataTLS AerospikeTLSConfigModel
@@ -119,96 +417,335 @@ func (p *AerospikeProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	user := withEnvironmentOverrideString(data.User_name.ValueString(), "AEROSPIKE_USER")
-	password := withEnvironmentOverrideString(data.Password.ValueString(), "AEROSPIKE_PASSWORD")
-	host := withEnvironmentOverrideString(data.Host.ValueString(), "AEROSPIKE_HOST")
-	port := withEnvironmentOverrideInt64(data.Port.ValueInt64(), "AEROSPIKE_PORT")
+	var toolsConf astoolsCluster
+	configFile := withEnvironmentOverrideString(data.Config_file.ValueString(), "AEROSPIKE_CONFIG_FILE")
+	if configFile != "" {
+		var confErr error
+		toolsConf, confErr = readAstoolsConfig(configFile, data.Config_instance.ValueString())
+		if confErr != nil {
+			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error reading aerospike tools config file", confErr.Error()))
+			return
+		}
+	}
+
+	user := withEnvironmentOverrideString(stringValueOrDefault(data.User_name, toolsConf.User), "AEROSPIKE_USER")
+	configPassword := stringValueOrDefault(data.Password, toolsConf.Password)
+	switch {
+	case !data.Password_file.IsNull():
+		var pwErr error
+		configPassword, pwErr = readPasswordFile(data.Password_file.ValueString())
+		if pwErr != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password_file"), "Error reading password file", pwErr.Error())
+			return
+		}
+	case !data.Password_command.IsNull():
+		var pwErr error
+		configPassword, pwErr = runPasswordCommand(ctx, data.Password_command.ValueString())
+		if pwErr != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password_command"), "Error running password command", pwErr.Error())
+			return
+		}
+	}
+	password := withEnvironmentOverrideString(configPassword, "AEROSPIKE_PASSWORD")
+	host := withEnvironmentOverrideString(stringValueOrDefault(data.Host, toolsConf.Host), "AEROSPIKE_HOST")
+	port := withEnvironmentOverrideInt64(int64ValueOrDefault(data.Port, toolsConf.Port), "AEROSPIKE_PORT")
+	host, port, hostErr := splitHost(host, port)
+	if hostErr != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("host"), "Invalid host", hostErr.Error())
+		return
+	}
+	var seeds []*as.Host
+	if !data.Host_srv_record.IsNull() {
+		var srvErr error
+		seeds, srvErr = resolveSRVSeeds(ctx, data.Host_srv_record.ValueString())
+		if srvErr != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("host_srv_record"), "Error resolving SRV record", srvErr.Error())
+			return
+		}
+		host, port = seeds[0].Name, int64(seeds[0].Port)
+	} else {
+		seeds = []*as.Host{as.NewHost(host, int(port))}
+	}
+	resp.Diagnostics.Append(missingConnectionSettings(host, port, user, password)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 	connectTimeout := withEnvironmentOverrideInt64(data.Connect_timeout.ValueInt64(), "AEROSPIKE_CONNECT_TIMEOUT")
+	infoTimeout := withEnvironmentOverrideInt64(data.Info_timeout.ValueInt64(), "AEROSPIKE_INFO_TIMEOUT")
 
 	cp := as.NewClientPolicy()
 	cp.User = user
 	cp.Password = password
+	switch authMode := withEnvironmentOverrideString(data.Auth_mode.ValueString(), "AEROSPIKE_AUTH_MODE"); authMode {
+	case "", authModeInternal:
+		cp.AuthMode = as.AuthModeInternal
+	case authModeExternal:
+		cp.AuthMode = as.AuthModeExternal
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root("auth_mode"), "Invalid auth_mode",
+			fmt.Sprintf("auth_mode must be %s or %s, got %q", authModeInternal, authModeExternal, authMode))
+		return
+	}
 	if connectTimeout != 0 {
 		cp.Timeout = time.Second * time.Duration(connectTimeout)
 	}
+	if !data.Connection_pool_size.IsNull() {
+		cp.ConnectionQueueSize = int(data.Connection_pool_size.ValueInt64())
+	}
+	if !data.Max_error_rate.IsNull() {
+		cp.MaxErrorRate = int(data.Max_error_rate.ValueInt64())
+	}
+	if !data.Error_rate_window.IsNull() {
+		cp.ErrorRateWindow = int(data.Error_rate_window.ValueInt64())
+	}
+	if !data.Fail_if_not_connected.IsNull() {
+		cp.FailIfNotConnected = data.Fail_if_not_connected.ValueBool()
+	}
+	readPolicy := as.NewPolicy()
+	if data.Rack_aware.ValueBool() {
+		if len(data.Rack_ids) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("rack_ids"), "Missing rack_ids", "rack_ids must be set when rack_aware is true")
+			return
+		}
+		cp.RackAware = true
+		for _, id := range data.Rack_ids {
+			cp.RackIds = append(cp.RackIds, int(id.ValueInt64()))
+		}
+		readPolicy.ReplicaPolicy = as.PREFER_RACK
+	}
 
 	//TLS
 	var tlsEnabled bool
 	var tlsConfig tls.Config
 
-	if data.TLS.IsNull() {
+	// setting AEROSPIKE_TLS_NAME or AEROSPIKE_ROOT_CA_FILE enables TLS, so the provider block can be empty in CI
+	_, envTLSName := os.LookupEnv("AEROSPIKE_TLS_NAME")
+	_, envRootCAFile := os.LookupEnv("AEROSPIKE_ROOT_CA_FILE")
+	if data.TLS.IsNull() && !toolsConf.TLSEnable && !envTLSName && !envRootCAFile {
 		tlsEnabled = false
 	} else {
 		tlsEnabled = true
-		data.TLS.As(ctx, &dataTLS, basetypes.ObjectAsOptions{})
+		if !data.TLS.IsNull() {
+			data.TLS.As(ctx, &dataTLS, basetypes.ObjectAsOptions{})
+		}
+		if tlsName := withEnvironmentOverrideString(stringValueOrDefault(dataTLS.TLSName, toolsConf.TLSName), "AEROSPIKE_TLS_NAME"); tlsName != "" {
+			dataTLS.TLSName = types.StringValue(tlsName)
+		}
+		if caFile := withEnvironmentOverrideString(stringValueOrDefault(dataTLS.RootCAFile, toolsConf.TLSCAFile), "AEROSPIKE_ROOT_CA_FILE"); caFile != "" {
+			dataTLS.RootCAFile = types.StringValue(caFile)
+		}
 
 		//read the root ca if supplied
 		if !dataTLS.RootCAFile.IsNull() {
-			file, err := os.Open(dataTLS.RootCAFile.ValueString())
+			roots, err := readRootCAFile(dataTLS.RootCAFile.ValueString())
 			if err != nil {
-				resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error reading root ca file", err.Error()))
+				resp.Diagnostics.AddAttributeError(path.Root("tls").AtName("root_ca_file"), "Error reading root ca file", err.Error())
 				return
 			}
-			defer file.Close()
+			tlsConfig.RootCAs = roots
+		}
+	}
 
-			// Get the file size
-			stat, err := file.Stat()
-			if err != nil {
-				resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error reading root ca file", err.Error()))
-				return
-			}
+	if cp.AuthMode == as.AuthModeExternal && !tlsEnabled {
+		resp.Diagnostics.AddAttributeError(path.Root("auth_mode"), "TLS required",
+			"auth_mode = \"external\" sends the password to the cluster in clear text and requires tls")
+		return
+	}
 
-			// Read the file into a byte slice
-			bs := make([]byte, stat.Size())
-			_, err = bufio.NewReader(file).Read(bs)
-			if err != nil && err != io.EOF {
-				resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error reading root ca file", err.Error()))
-				return
-			}
+	clientType := as.CTNative
+	if data.Client_type.ValueString() == "proxy" {
+		clientType = as.CTProxy
+		if !proxyClientBuilt {
+			resp.Diagnostics.AddAttributeError(path.Root("client_type"), "Proxy client not available",
+				"This build of the provider doesn't include the proxy client used by client_type = \"proxy\". "+
+					"Use a released binary, or build the provider with -tags=as_proxy (make install does)")
+			return
+		}
+		if !tlsEnabled {
+			resp.Diagnostics.AddAttributeError(path.Root("tls"), "TLS required",
+				"client_type = \"proxy\" requires tls to be configured")
+			return
+		}
+	}
+
+	var retryPol *retryPolicy
+	if !data.Retry_policy.IsNull() {
+		var retryPolicyData AerospikeRetryPolicyModel
+		resp.Diagnostics.Append(data.Retry_policy.As(ctx, &retryPolicyData, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		var attrName string
+		var retryErr error
+		retryPol, attrName, retryErr = newRetryPolicy(retryPolicyData)
+		if retryErr != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("retry_policy").AtName(attrName), "Invalid retry_policy", retryErr.Error())
+			return
+		}
+	}
 
-			roots := x509.NewCertPool()
-			ok := roots.AppendCertsFromPEM(bs)
-			if !ok {
-				resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error reading root ca file", err.Error()))
+	if !data.Min_connected_nodes.IsNull() && clientType == as.CTProxy {
+		resp.Diagnostics.AddAttributeError(path.Root("min_connected_nodes"), "min_connected_nodes is not supported",
+			"min_connected_nodes can't be used with client_type = \"proxy\", the proxy doesn't expose the cluster nodes")
+		return
+	}
+
+	var waitForCluster AerospikeWaitForClusterModel
+	var waitDeadline time.Time
+	if !data.Wait_for_cluster.IsNull() {
+		resp.Diagnostics.Append(data.Wait_for_cluster.As(ctx, &waitForCluster, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if clientType == as.CTProxy {
+			resp.Diagnostics.AddAttributeError(path.Root("wait_for_cluster"), "wait_for_cluster is not supported",
+				"wait_for_cluster can't be used with client_type = \"proxy\", the proxy doesn't expose the cluster nodes")
+			return
+		}
+		timeout := defaultWaitForClusterTimeout
+		if !waitForCluster.Timeout.IsNull() {
+			var durErr error
+			timeout, durErr = time.ParseDuration(waitForCluster.Timeout.ValueString())
+			if durErr != nil || timeout <= 0 {
+				resp.Diagnostics.AddAttributeError(path.Root("wait_for_cluster").AtName("timeout"), "Invalid timeout",
+					"timeout must be a positive duration such as \"2m\", got "+waitForCluster.Timeout.ValueString())
 				return
 			}
-			tlsConfig.RootCAs = roots
 		}
+		waitDeadline = time.Now().Add(timeout)
 	}
 
-	ash := as.NewHost(host, int(port))
 	if tlsEnabled {
 		if !dataTLS.TLSName.IsNull() {
-			ash.TLSName = dataTLS.TLSName.ValueString()
+			for _, seed := range seeds {
+				seed.TLSName = dataTLS.TLSName.ValueString()
+			}
 		}
 		cp.TlsConfig = &tlsConfig
 	}
-	tempConn, err = as.CreateClientWithPolicyAndHost(as.CTNative, cp, ash)
+	connect := func() (as.ClientIfc, as.Error) {
+		client, err := as.CreateClientWithPolicyAndHost(clientType, cp, seeds...)
+		if err != nil && startedDisconnected(client) {
+			// fail_if_not_connected is false, the client keeps connecting in the background
+			tflog.Warn(ctx, "the client isn't connected to the cluster yet: "+err.Error())
+			return client, nil
+		}
+
+		return client, err
+	}
+	tempConn, err = connect()
+	// the nodes may still be starting when wait_for_cluster is set
+	for err != nil && time.Now().Add(clusterStablePollInterval).Before(waitDeadline) {
+		tflog.Info(ctx, "waiting for the cluster to accept connections: "+err.Error())
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError("Error connecting to Aerospike", "Canceled while waiting for the cluster: "+ctx.Err().Error())
+			return
+		case <-time.After(clusterStablePollInterval):
+		}
+		tempConn, err = connect()
+	}
 	if err != nil {
 		if err.Matches(astypes.TIMEOUT) {
 			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Timeout connecting to Aerospike",
 				"Timeout connecting to Aerospike cluster "+host+" "+err.Error()))
 			return
 		} else {
-			panic(err)
+			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Error connecting to Aerospike",
+				"Error connecting to Aerospike cluster "+host+" "+err.Error()))
+			return
+		}
+	}
+
+	if !data.Wait_for_cluster.IsNull() {
+		if waitErr := waitForNodes(ctx, tempConn, int(waitForCluster.Expected_nodes.ValueInt64()), waitDeadline); waitErr != nil {
+			tempConn.Close()
+			resp.Diagnostics.AddAttributeError(path.Root("wait_for_cluster"), "Cluster did not form", waitErr.Error())
+			return
 		}
 	}
 
-	asConn.client = &tempConn
+	asConn.client = tempConn
+	asConn.clientType = clientType
+	asConn.clientPolicy = cp
+	asConn.hosts = seeds
+	asConn.adminPolicy = as.NewAdminPolicy()
+	asConn.infoPolicy = newInfoPolicy(infoTimeout)
+	asConn.readPolicy = readPolicy
+	asConn.userName = user
+	asConn.debugInfoResponses = data.Debug_info_responses.ValueBool()
+	asConn.blockDestructive = !data.Allow_destructive.IsNull() && !data.Allow_destructive.ValueBool()
+	asConn.readOnly = data.Read_only.ValueBool()
+	asConn.retryPolicy = retryPol
+	asConn.infoTarget = data.Info_target.ValueString()
+	if !data.Audit_log_file.IsNull() {
+		asConn.auditLog = &auditLog{path: data.Audit_log_file.ValueString()}
+	}
+
+	// Aerospike Cloud is always enterprise with security, and the proxy client can't detect it anyway
+	asConn.enterprise = true
+	asConn.securityEnabled = true
+	if asConn.supportsInfo() {
+		if edErr := asConn.detectEdition(ctx); edErr != nil {
+			resp.Diagnostics.AddWarning("Unable to detect the server edition",
+				"Enterprise only resources will be used without checking the edition: "+edErr.Error())
+		}
+
+		peersCommand := "peers-clear-std"
+		if tlsEnabled {
+			peersCommand = "peers-tls-std"
+		}
+		resp.Diagnostics.Append(asConn.checkConnectedNodes(ctx, peersCommand, int(data.Min_connected_nodes.ValueInt64()))...)
+		if resp.Diagnostics.HasError() {
+			tempConn.Close()
+			return
+		}
+	}
 
 	resp.DataSourceData = &asConn
 	resp.ResourceData = &asConn
+	resp.EphemeralResourceData = &asConn
 }
 
 func (p *AerospikeProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewAerospikeUser,
 		NewAerospikeRole,
+		NewAerospikeConfig,
+		NewAerospikeRecord,
+		NewAerospikeRecords,
+		NewAerospikeUserRoles,
+		NewAerospikeRolePrivilege,
+		NewAerospikeConfigHistogram,
+		NewAerospikeConfigXdr,
+		NewAerospikeConfigCluster,
+		NewAerospikeInfoCommand,
+		NewAerospikeXdrFilter,
+		NewAerospikeWaitForMigrations,
 	}
 }
 
 func (p *AerospikeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewAerospikeUsersDataSource,
+		NewAerospikeRolesDataSource,
+		NewAerospikeEditionDataSource,
+		NewAerospikeQuotaUsageDataSource,
+		NewAerospikeStopWritesDataSource,
+		NewAerospikeClusterStableDataSource,
+		NewAerospikeRacksDataSource,
+		NewAerospikeSecurityReportDataSource,
+		NewAerospikeConfigComplianceDataSource,
+		NewAerospikeSecurityExportDataSource,
+		NewAerospikeSetDataSource,
+		NewAerospikeSindexStatDataSource,
+	}
+}
+
+func (p *AerospikeProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAerospikeTemporaryUser,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -218,3 +755,58 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// missingConnectionSettings returns an error for every setting needed to connect that wasn't set in the provider
+// block, the tools config file or the environment. user_name and password are only needed together, for clusters
+// with security enabled.
+func missingConnectionSettings(host string, port int64, user, password string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if host == "" {
+		diags.AddAttributeError(path.Root("host"), "Missing Aerospike host",
+			"Set host or host_srv_record in the provider block, host in config_file, or the AEROSPIKE_HOST environment variable")
+	}
+	if port == 0 {
+		diags.AddAttributeError(path.Root("port"), "Missing Aerospike port",
+			"Set port in the provider block or config_file, or the AEROSPIKE_PORT environment variable")
+	}
+	diags.Append(missingCredentials(user, password != "")...)
+
+	return diags
+}
+
+// missingCredentials returns an error when only one of user_name and a password source is set.
+func missingCredentials(user string, passwordSet bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if user != "" && !passwordSet {
+		diags.AddAttributeError(path.Root("password"), "Missing Aerospike password",
+			"user_name is set but the password isn't. Set password, password_file or password_command in the provider block, "+
+				"password in config_file, or the AEROSPIKE_PASSWORD environment variable")
+	}
+	if user == "" && passwordSet {
+		diags.AddAttributeError(path.Root("user_name"), "Missing Aerospike user name",
+			"A password is set but user_name isn't. Set user_name in the provider block or config_file, "+
+				"or the AEROSPIKE_USER environment variable")
+	}
+
+	return diags
+}
+
+// resolveSRVSeeds resolves a DNS SRV record into the seed hosts, in the order of their priority and weight.
+func resolveSRVSeeds(ctx context.Context, record string) ([]*as.Host, error) {
+	_, addrs, err := net.DefaultResolver.LookupSRV(ctx, "", "", record)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("SRV record %s has no targets", record)
+	}
+
+	seeds := make([]*as.Host, 0, len(addrs))
+	for _, addr := range addrs {
+		seeds = append(seeds, as.NewHost(strings.TrimSuffix(addr.Target, "."), int(addr.Port)))
+	}
+
+	return seeds, nil
+}
@@ -4,8 +4,14 @@
 package provider
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 )
@@ -23,3 +29,114 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func TestMissingConnectionSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     string
+		port     int64
+		user     string
+		password string
+		want     []path.Path
+	}{
+		{name: "complete", host: "localhost", port: 3000, user: "admin", password: "admin"},
+		{name: "no security", host: "localhost", port: 3000},
+		{name: "nothing set", want: []path.Path{path.Root("host"), path.Root("port")}},
+		{name: "no password", host: "localhost", port: 3000, user: "admin", want: []path.Path{path.Root("password")}},
+		{name: "no user", host: "localhost", port: 3000, password: "admin", want: []path.Path{path.Root("user_name")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := missingConnectionSettings(tt.host, tt.port, tt.user, tt.password)
+			if len(diags) != len(tt.want) {
+				t.Fatalf("missingConnectionSettings() = %v, want errors for %v", diags, tt.want)
+			}
+			for i, d := range diags {
+				withPath, ok := d.(interface{ Path() path.Path })
+				if !ok || !withPath.Path().Equal(tt.want[i]) {
+					t.Errorf("error %d = %v, want an error for %v", i, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	conn := newFakeConnection(newFakeClient(), "admin")
+	if diags := conn.checkWritable("Creating aerospike_user"); diags.HasError() {
+		t.Errorf("checkWritable() = %v, want no errors by default", diags)
+	}
+
+	conn.readOnly = true
+	if diags := conn.checkWritable("Creating aerospike_user"); !diags.HasError() {
+		t.Error("checkWritable() returned no errors with read_only = true")
+	}
+}
+
+func TestDetectEditionError(t *testing.T) {
+	// the fake client has no nodes to send info commands to
+	conn := newFakeConnection(newFakeClient(), "admin")
+	if err := conn.detectEdition(context.Background()); err == nil {
+		t.Fatal("detectEdition() = nil, want an error")
+	}
+	if !conn.enterprise || !conn.securityEnabled {
+		t.Error("detectEdition() changed the edition and security flags although it couldn't read them")
+	}
+}
+
+func TestParsePeers(t *testing.T) {
+	peers := parsePeers("3,3000,[[BB9020011AC4202,,[172.17.0.2]],[BB9030011AC4202,db2,[172.17.0.3:3100,[::1]:3100]]]")
+	want := map[string]string{"BB9020011AC4202": "172.17.0.2", "BB9030011AC4202": "172.17.0.3:3100,[::1]:3100"}
+	if !reflect.DeepEqual(peers, want) {
+		t.Errorf("parsePeers() = %v, want %v", peers, want)
+	}
+
+	if peers := parsePeers("0,3000,[]"); len(peers) != 0 {
+		t.Errorf("parsePeers() = %v for a single node cluster, want no peers", peers)
+	}
+}
+
+func TestUnreachablePeers(t *testing.T) {
+	peers := map[string]string{"BB902": "172.17.0.2", "BB903": "172.17.0.3", "BB904": "172.17.0.4"}
+	got := unreachablePeers(peers, []string{"BB901", "BB903"})
+	want := []string{"BB902 (172.17.0.2)", "BB904 (172.17.0.4)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unreachablePeers() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckConnectedNodes(t *testing.T) {
+	conn := newFakeConnection(newFakeClient(), "admin")
+
+	// the fake client has no nodes
+	diags := conn.checkConnectedNodes(context.Background(), "peers-clear-std", 0)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Errorf("checkConnectedNodes() = %v, want a single warning", diags)
+	}
+
+	diags = conn.checkConnectedNodes(context.Background(), "peers-clear-std", 1)
+	if !diags.HasError() {
+		t.Error("checkConnectedNodes() returned no errors with fewer nodes than min_connected_nodes")
+	}
+}
+
+func TestOverrideKey(t *testing.T) {
+	host := as.NewHost("db1", 3000)
+	key := overrideKey(host, "admin", "pass1")
+
+	if overrideKey(host, "admin", "pass1") != key {
+		t.Errorf("overrideKey() isn't stable")
+	}
+	if overrideKey(host, "admin", "pass2") == key {
+		t.Errorf("overrideKey() is the same for a different password")
+	}
+	if strings.Contains(key, "pass1") {
+		t.Errorf("overrideKey() = %s contains the password", key)
+	}
+	tlsHost := as.NewHost("db1", 3000)
+	tlsHost.TLSName = "db1-tls"
+	if overrideKey(tlsHost, "admin", "pass1") == key {
+		t.Errorf("overrideKey() is the same for a different TLS name")
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !as_proxy
+
+package provider
+
+// proxyClientBuilt reports whether the binary includes the proxy client used by client_type = "proxy", which the
+// Aerospike client only builds with the as_proxy build tag.
+const proxyClientBuilt = false
@@ -0,0 +1,730 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeConfig{}
+var _ resource.ResourceWithValidateConfig = &AerospikeConfig{}
+var _ resource.ResourceWithModifyPlan = &AerospikeConfig{}
+
+func NewAerospikeConfig() resource.Resource {
+	return &AerospikeConfig{}
+}
+
+// defaultMigrationsTimeout is the default migrations_timeout in seconds.
+const defaultMigrationsTimeout = 3600
+
+// configOriginalValuesKey is the private state key of the values the parameters had before terraform first set them.
+const configOriginalValuesKey = "original_values"
+
+// AerospikeConfig defines the resource implementation.
+type AerospikeConfig struct {
+	asConn *asConnection
+}
+
+// AerospikeConfigModel describes the resource data model.
+type AerospikeConfigModel struct {
+	Context      types.String            `tfsdk:"context"`
+	Namespace    types.String            `tfsdk:"namespace"`
+	DC           types.String            `tfsdk:"dc"`
+	Parameters   map[string]types.String `tfsdk:"parameters"`
+	Info_timeout types.Int64             `tfsdk:"info_timeout"`
+
+	Wait_for_migrations types.Bool  `tfsdk:"wait_for_migrations"`
+	Migrations_timeout  types.Int64 `tfsdk:"migrations_timeout"`
+	Restore_on_destroy  types.Bool  `tfsdk:"restore_on_destroy"`
+	Dry_run             types.Bool  `tfsdk:"dry_run"`
+
+	Inconsistent_nodes types.List `tfsdk:"inconsistent_nodes"`
+	Planned_commands   types.List `tfsdk:"planned_commands"`
+}
+
+func (r *AerospikeConfig) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config"
+}
+
+func (r *AerospikeConfig) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Generic Aerospike dynamic configuration. Parameters are passed verbatim to set-config and read back with get-config. " +
+			"Destroying the resource leaves the parameters at their current values unless restore_on_destroy is set",
+
+		Attributes: map[string]schema.Attribute{
+			"context": schema.StringAttribute{
+				Description: "Configuration context. One of service, network, namespace, xdr or security. The network context holds the dynamic heartbeat settings",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("service", "network", "namespace", "xdr", "security"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace. Required for the namespace context, optional for the xdr context",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dc": schema.StringAttribute{
+				Description: "XDR datacenter. Only valid for the xdr context",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"parameters": schema.MapAttribute{
+				Description: "Map of configuration parameter names to values, as they are named in set-config",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"info_timeout": schema.Int64Attribute{
+				Description: "Timeout in seconds for the info commands of this resource. Defaults to the provider info_timeout",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 600),
+				},
+			},
+			"wait_for_migrations": schema.BoolAttribute{
+				Description: "Wait for the cluster to finish migrating after the parameters are set, e.g. after changing rack-id",
+				Optional:    true,
+			},
+			"migrations_timeout": schema.Int64Attribute{
+				Description: fmt.Sprintf("Seconds to wait for migrations when wait_for_migrations is set. Defaults to %d", defaultMigrationsTimeout),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("wait_for_migrations")),
+				},
+			},
+			"restore_on_destroy": schema.BoolAttribute{
+				Description: "Set the parameters back to the values they had before terraform first set them when the resource is destroyed " +
+					"or a parameter is removed from parameters",
+				Optional: true,
+			},
+			"dry_run": schema.BoolAttribute{
+				Description: "Don't send the set-config commands. They are recorded in planned_commands and reported in a warning, to be " +
+					"reviewed and run manually in a change window. Refreshes still read the cluster, so the parameters show as changed until the commands are run",
+				Optional: true,
+			},
+			"inconsistent_nodes": schema.ListAttribute{
+				Description: "Nodes where a managed parameter differs from the value set by terraform, found during the last refresh",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"planned_commands": schema.ListAttribute{
+				Description: "set-config commands issued by the apply, shown in the plan so they can be reviewed before approving. " +
+					"Values of parameters that carry credentials are redacted",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *AerospikeConfig) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AerospikeConfigModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Context.IsUnknown() {
+		return
+	}
+
+	configContext := data.Context.ValueString()
+
+	if configContext == "namespace" && data.Namespace.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("namespace"), "Missing namespace",
+			"namespace must be set when context is \"namespace\"")
+	}
+	if configContext != "namespace" && configContext != "xdr" && !data.Namespace.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("namespace"), "Invalid namespace",
+			"namespace can only be set for the namespace and xdr contexts")
+	}
+	if configContext != "xdr" && !data.DC.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("dc"), "Invalid dc", "dc can only be set for the xdr context")
+	}
+}
+
+func (r *AerospikeConfig) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_config is not supported",
+			"aerospike_config uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+// ModifyPlan checks the plan against the cluster before anything is sent, so an apply doesn't stop half way through
+// the parameters.
+func (r *AerospikeConfig) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.asConn == nil {
+		return
+	}
+
+	// parameters may be unknown until apply, so the plan is read attribute by attribute instead of into the model
+	var plan AerospikeConfigModel
+	var planParameters types.Map
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("context"), &plan.Context)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &plan.Namespace)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("dc"), &plan.DC)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parameters"), &planParameters)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("info_timeout"), &plan.Info_timeout)...)
+
+	if resp.Diagnostics.HasError() || plan.Context.IsUnknown() || plan.Namespace.IsUnknown() || plan.DC.IsUnknown() ||
+		planParameters.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(planParameters.ElementsAs(ctx, &plan.Parameters, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.planCommands(ctx, req, resp, plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configContext := plan.Context.ValueString()
+	if (configContext == "xdr" || configContext == "security") && !r.asConn.enterprise {
+		resp.Diagnostics.AddAttributeError(path.Root("context"), "Enterprise Edition required",
+			"The "+configContext+" context requires Enterprise Edition. The cluster runs Community Edition")
+		return
+	}
+
+	if !plan.Namespace.IsNull() {
+		resp.Diagnostics.Append(r.asConn.checkNamespace(ctx, path.Root("namespace"), plan.Namespace.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	command := getConfigCommand(configContext, plan.Namespace.ValueString(), plan.DC.ValueString())
+	res, err := r.asConn.infoAnyNodeWithPolicy(ctx, r.infoPolicy(plan), command)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to check the configuration", err.Error())
+		return
+	}
+	if isInfoError(res) {
+		resp.Diagnostics.AddError("Invalid configuration context",
+			fmt.Sprintf("%s returned %q. Check that the namespace or dc exists", command, res))
+		return
+	}
+	current := parseInfoParams(res)
+
+	for _, k := range sortedKeys(plan.Parameters) {
+		if _, ok := current[k]; !ok {
+			detail := fmt.Sprintf("%s is not reported by %s, so it can't be set and verified by this resource", k, command)
+			if hint := r.asConn.configParamVersionError(ctx, configContext, k); hint != "" {
+				detail = hint
+			}
+			resp.Diagnostics.AddAttributeError(path.Root("parameters").AtMapKey(k), "Unknown parameter", detail)
+		}
+	}
+
+	if configContext == "namespace" {
+		resp.Diagnostics.Append(checkNamespaceTTL(plan.Parameters, current)...)
+		if !req.Plan.Raw.Equal(req.State.Raw) {
+			resp.Diagnostics.Append(r.asConn.warnStopWrites(ctx, path.Root("namespace"), plan.Namespace.ValueString())...)
+		}
+	}
+}
+
+// checkNamespaceTTL rejects a non zero default-ttl while nsup is disabled, which the server refuses unless
+// allow-ttl-without-nsup is set.
+func checkNamespaceTTL(params map[string]types.String, current map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	value := func(k string) string {
+		if v, ok := params[k]; ok && !v.IsUnknown() {
+			return v.ValueString()
+		}
+		return current[k]
+	}
+
+	defaultTTL := value("default-ttl")
+	if defaultTTL == "" || defaultTTL == "0" {
+		return diags
+	}
+	if value("nsup-period") == "0" && value("allow-ttl-without-nsup") != "true" {
+		diags.AddAttributeError(path.Root("parameters").AtMapKey("default-ttl"), "TTL requires nsup",
+			"default-ttl can't be set while nsup-period is 0. Set nsup-period, or allow-ttl-without-nsup to true")
+	}
+
+	return diags
+}
+
+func (r *AerospikeConfig) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_config")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Dry_run.ValueBool() {
+		previous, diags := r.setParameters(ctx, data, data.Parameters)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(setOriginalValues(ctx, resp.Private, previous)...)
+
+		resp.Diagnostics.Append(r.waitForMigrations(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// setParameters verified the values on every node, a dry run didn't change them
+	data.Inconsistent_nodes = stringList(nil)
+	if data.Planned_commands.IsUnknown() {
+		data.Planned_commands = stringList(plannedConfigCommands(data, nil, nil))
+	}
+	resp.Diagnostics.Append(dryRunWarning(ctx, data)...)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfig) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeConfigModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command := getConfigCommand(data.Context.ValueString(), data.Namespace.ValueString(), data.DC.ValueString())
+	responses, err := r.asConn.infoAllNodesWithPolicy(ctx, r.infoPolicy(data), command)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading configuration", err.Error())
+		return
+	}
+	for _, node := range sortedKeys(responses) {
+		msg, ok := infoErrorMessage(responses[node])
+		if !ok {
+			continue
+		}
+		// the namespace was removed from the cluster, there is nothing left to manage
+		if namespace := data.Namespace.ValueString(); namespace != "" {
+			exists, err := r.asConn.namespaceExists(ctx, namespace)
+			if err != nil {
+				resp.Diagnostics.AddError("Error reading configuration", err.Error())
+				return
+			}
+			if !exists {
+				resp.Diagnostics.AddWarning("Namespace removed",
+					fmt.Sprintf("Namespace %s no longer exists in the cluster, removing its configuration from the state", namespace))
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("Error reading configuration", fmt.Sprintf("%s returned %s on node %s", command, msg, node))
+		return
+	}
+
+	params, inconsistentNodes, diags := compareNodeParameters(data.Parameters, responses)
+	resp.Diagnostics.Append(diags...)
+	data.Parameters = params
+	data.Inconsistent_nodes = stringList(inconsistentNodes)
+
+	tflog.Trace(ctx, "read configuration with "+command)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfig) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_config")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeConfigModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed := make(map[string]types.String)
+	for k, v := range plan.Parameters {
+		if stateValue, ok := state.Parameters[k]; !ok || !v.Equal(stateValue) {
+			changed[k] = v
+		}
+	}
+
+	originals, diags := getOriginalValues(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// parameters that are no longer managed are restored now, as they would be on destroy
+	restore := make(map[string]string)
+	for k := range state.Parameters {
+		if _, ok := plan.Parameters[k]; ok {
+			continue
+		}
+		if v, ok := originals[k]; ok && plan.Restore_on_destroy.ValueBool() {
+			restore[k] = v
+		}
+		delete(originals, k)
+	}
+
+	// a dry run leaves the cluster and the original values as they are
+	if !plan.Dry_run.ValueBool() {
+		previous, diags := r.setParameters(ctx, plan, changed)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for k, v := range previous {
+			if _, ok := originals[k]; !ok {
+				originals[k] = v
+			}
+		}
+
+		_, diags = r.setValues(ctx, plan, restore)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(setOriginalValues(ctx, resp.Private, originals)...)
+
+		if len(changed) > 0 {
+			resp.Diagnostics.Append(r.waitForMigrations(ctx, plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	plan.Inconsistent_nodes = stringList(nil)
+	if plan.Planned_commands.IsUnknown() {
+		plan.Planned_commands = stringList(plannedConfigCommands(plan, state.Parameters, restore))
+	}
+	resp.Diagnostics.Append(dryRunWarning(ctx, plan)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeConfig) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_config")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Restore_on_destroy.ValueBool() {
+		// Dynamic configuration can't be unset, the parameters stay at their current values
+		tflog.Trace(ctx, "removed configuration for context "+data.Context.ValueString()+" from state, server values are unchanged")
+		return
+	}
+
+	originals, diags := getOriginalValues(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	restore := make(map[string]string, len(originals))
+	for k := range data.Parameters {
+		if v, ok := originals[k]; ok {
+			restore[k] = v
+		}
+	}
+
+	if data.Dry_run.ValueBool() {
+		// only the restore commands would be sent
+		data.Parameters = nil
+		data.Planned_commands = stringList(plannedConfigCommands(data, nil, restore))
+		resp.Diagnostics.Append(dryRunWarning(ctx, data)...)
+		return
+	}
+
+	_, diags = r.setValues(ctx, data, restore)
+	resp.Diagnostics.Append(diags...)
+
+	tflog.Trace(ctx, fmt.Sprintf("restored %d parameters of context %s", len(restore), data.Context.ValueString()))
+}
+
+// dryRunWarning reports the planned commands that weren't sent because dry_run is set.
+func dryRunWarning(ctx context.Context, data AerospikeConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !data.Dry_run.ValueBool() || len(data.Planned_commands.Elements()) == 0 {
+		return diags
+	}
+
+	commands := make([]string, 0, len(data.Planned_commands.Elements()))
+	diags.Append(data.Planned_commands.ElementsAs(ctx, &commands, false)...)
+
+	diags.AddWarning("Configuration not applied (dry run)",
+		"dry_run is set, the following commands were not sent. Run them on every node to apply the change, "+
+			"with the real values of redacted parameters:\n"+strings.Join(commands, "\n"))
+
+	return diags
+}
+
+// setParameters sends a set-config command for every parameter to all nodes, then verifies the values with get-config.
+// The values the parameters had before are returned.
+func (r *AerospikeConfig) setParameters(ctx context.Context, data AerospikeConfigModel, params map[string]types.String) (map[string]string, diag.Diagnostics) {
+	values := make(map[string]string, len(params))
+	for k, v := range params {
+		values[k] = v.ValueString()
+	}
+
+	return r.setValues(ctx, data, values)
+}
+
+// setValues is setParameters for plain values.
+func (r *AerospikeConfig) setValues(ctx context.Context, data AerospikeConfigModel, values map[string]string) (map[string]string, diag.Diagnostics) {
+	return r.asConn.setConfig(ctx, r.infoPolicy(data), data.Context.ValueString(), data.Namespace.ValueString(),
+		data.DC.ValueString(), values, func(param string) path.Path {
+			return path.Root("parameters").AtMapKey(param)
+		})
+}
+
+// waitForMigrations blocks until migrations complete when wait_for_migrations is set.
+func (r *AerospikeConfig) waitForMigrations(ctx context.Context, data AerospikeConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !data.Wait_for_migrations.ValueBool() {
+		return diags
+	}
+
+	timeout := int64(defaultMigrationsTimeout)
+	if !data.Migrations_timeout.IsNull() {
+		timeout = data.Migrations_timeout.ValueInt64()
+	}
+
+	if err := r.asConn.waitForMigrations(ctx, r.infoPolicy(data), time.Duration(timeout)*time.Second, clusterStablePollInterval); err != nil {
+		// the parameters are already set. The state isn't saved, so the next apply sets them again and retries the wait
+		diags.AddError("Migrations did not complete", err.Error())
+	}
+
+	return diags
+}
+
+// compareNodeParameters refreshes the managed parameters from the get-config responses of every node. A parameter
+// that differs between nodes is refreshed with the first value that differs from the managed one, so the plan
+// re-applies it, and the nodes holding other values are reported.
+func compareNodeParameters(managed map[string]types.String, responses map[string]string) (map[string]types.String, []string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := make(map[string]types.String, len(managed))
+	for k, v := range managed {
+		params[k] = v
+	}
+	inconsistent := make(map[string]bool)
+
+	for _, node := range sortedKeys(responses) {
+		current := parseInfoParams(responses[node])
+		for _, k := range sortedKeys(managed) {
+			v, ok := current[k]
+			if !ok || v == managed[k].ValueString() {
+				continue
+			}
+			if params[k].Equal(managed[k]) {
+				params[k] = types.StringValue(v)
+			}
+			inconsistent[node] = true
+			diags.AddAttributeWarning(path.Root("parameters").AtMapKey(k), "Configuration drift",
+				fmt.Sprintf("Node %s reports %s=%q, terraform manages it as %q", node, k, v, managed[k].ValueString()))
+		}
+	}
+
+	return params, sortedKeys(inconsistent), diags
+}
+
+// infoPolicy returns the info policy for the resource's info_timeout, or the provider's when it isn't set.
+func (r *AerospikeConfig) infoPolicy(data AerospikeConfigModel) *as.InfoPolicy {
+	if data.Info_timeout.IsNull() || data.Info_timeout.IsUnknown() {
+		return r.asConn.infoPolicy
+	}
+
+	return newInfoPolicy(data.Info_timeout.ValueInt64())
+}
+
+// privateState is implemented by the private state of the resource requests and responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getOriginalValues returns the values the managed parameters had before terraform first set them. Resources created
+// before the values were captured have none.
+func getOriginalValues(ctx context.Context, private privateState) (map[string]string, diag.Diagnostics) {
+	values := make(map[string]string)
+
+	data, diags := private.GetKey(ctx, configOriginalValuesKey)
+	if diags.HasError() || len(data) == 0 {
+		return values, diags
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		diags.AddError("Error reading private state", err.Error())
+	}
+
+	return values, diags
+}
+
+// setOriginalValues saves the values the managed parameters had before terraform first set them.
+func setOriginalValues(ctx context.Context, private privateState, values map[string]string) diag.Diagnostics {
+	data, err := json.Marshal(values)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Error saving private state", err.Error())
+		return diags
+	}
+
+	return private.SetKey(ctx, configOriginalValuesKey, data)
+}
+
+// planCommands sets planned_commands when the plan changes the resource. An unchanged resource keeps the commands of
+// the last apply, so it doesn't show a diff.
+func (r *AerospikeConfig) planCommands(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan AerospikeConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var planned types.List
+	var restoreOnDestroy types.Bool
+	var prior map[string]types.String
+
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("planned_commands"), &planned)...)
+	diags.Append(req.Plan.GetAttribute(ctx, path.Root("restore_on_destroy"), &restoreOnDestroy)...)
+	if diags.HasError() || !planned.IsUnknown() || restoreOnDestroy.IsUnknown() {
+		return diags
+	}
+	for _, v := range plan.Parameters {
+		if v.IsUnknown() {
+			return diags
+		}
+	}
+
+	restore := make(map[string]string)
+	if !req.State.Raw.IsNull() {
+		diags.Append(req.State.GetAttribute(ctx, path.Root("parameters"), &prior)...)
+		originals, origDiags := getOriginalValues(ctx, req.Private)
+		diags.Append(origDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		for k := range prior {
+			if _, ok := plan.Parameters[k]; ok {
+				continue
+			}
+			if v, ok := originals[k]; ok && restoreOnDestroy.ValueBool() {
+				restore[k] = v
+			}
+		}
+	}
+
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("planned_commands"), stringList(plannedConfigCommands(plan, prior, restore)))...)
+
+	return diags
+}
+
+// plannedConfigCommands returns the set-config commands that set the parameters that differ from prior, followed by
+// the commands that restore the parameters in restore. Sensitive values are redacted.
+func plannedConfigCommands(data AerospikeConfigModel, prior map[string]types.String, restore map[string]string) []string {
+	commands := make([]string, 0)
+
+	configContext, namespace, dc := data.Context.ValueString(), data.Namespace.ValueString(), data.DC.ValueString()
+	for _, k := range sortedKeys(data.Parameters) {
+		if v, ok := prior[k]; ok && v.Equal(data.Parameters[k]) {
+			continue
+		}
+		commands = append(commands, redactInfoCommand(setConfigCommand(configContext, namespace, dc, k, data.Parameters[k].ValueString())))
+	}
+	for _, k := range sortedKeys(restore) {
+		commands = append(commands, redactInfoCommand(setConfigCommand(configContext, namespace, dc, k, restore[k])))
+	}
+
+	return commands
+}
+
+// stringList converts a list of strings to a terraform list.
+func stringList(values []string) types.List {
+	elements := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, types.StringValue(v))
+	}
+
+	return types.ListValueMust(types.StringType, elements)
+}
@@ -0,0 +1,350 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeConfigCluster{}
+var _ resource.ResourceWithValidateConfig = &AerospikeConfigCluster{}
+var _ resource.ResourceWithImportState = &AerospikeConfigCluster{}
+
+// defaultReclusterTimeout is the default number of seconds to wait for the nodes to agree on a cluster key after a
+// recluster.
+const defaultReclusterTimeout = 300
+
+func NewAerospikeConfigCluster() resource.Resource {
+	return &AerospikeConfigCluster{}
+}
+
+// AerospikeConfigCluster defines the resource implementation.
+type AerospikeConfigCluster struct {
+	asConn *asConnection
+}
+
+// AerospikeConfigClusterModel describes the resource data model.
+type AerospikeConfigClusterModel struct {
+	Cluster_name      types.String            `tfsdk:"cluster_name"`
+	Parameters        map[string]types.String `tfsdk:"parameters"`
+	Recluster         types.Bool              `tfsdk:"recluster"`
+	Recluster_timeout types.Int64             `tfsdk:"recluster_timeout"`
+}
+
+func (r *AerospikeConfigCluster) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_cluster"
+}
+
+func (r *AerospikeConfigCluster) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Cluster identity settings such as the cluster name. Only one instance should be defined per cluster. " +
+			"Destroying the resource leaves the settings at their current values",
+
+		Attributes: map[string]schema.Attribute{
+			"cluster_name": schema.StringAttribute{
+				Description: "Name of the cluster. Nodes only join nodes with the same name, so it's changed on every node " +
+					"before the cluster is reclustered. Read from the cluster when not set",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"parameters": schema.MapAttribute{
+				Description: "Other dynamic cluster wide service context parameters, e.g. min-cluster-size, as they are named in set-config",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"recluster": schema.BoolAttribute{
+				Description: "Recluster after cluster_name changes and wait for the nodes to agree on a cluster key. Defaults to true",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			"recluster_timeout": schema.Int64Attribute{
+				Description: fmt.Sprintf("Seconds to wait for the cluster to form after a recluster. Defaults to %d", defaultReclusterTimeout),
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeConfigCluster) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var parameters types.Map
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+
+	if resp.Diagnostics.HasError() || parameters.IsNull() || parameters.IsUnknown() {
+		return
+	}
+
+	if _, ok := parameters.Elements()["cluster-name"]; ok {
+		resp.Diagnostics.AddAttributeError(path.Root("parameters").AtMapKey("cluster-name"), "Invalid parameter",
+			"cluster-name is managed with the cluster_name attribute")
+	}
+}
+
+func (r *AerospikeConfigCluster) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_config_cluster is not supported",
+			"aerospike_config_cluster uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeConfigCluster) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_config_cluster")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigClusterModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "set cluster configuration")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigCluster) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeConfigClusterModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read cluster configuration")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigCluster) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_config_cluster")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeConfigClusterModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeConfigCluster) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_config_cluster")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Dynamic configuration can't be unset, the parameters stay at their current values
+	tflog.Trace(ctx, "removed cluster configuration from state, server values are unchanged")
+}
+
+// ImportState ignores the id, there is only one set of cluster settings.
+func (r *AerospikeConfigCluster) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	data := AerospikeConfigClusterModel{
+		Cluster_name: types.StringUnknown(),
+		Recluster:    types.BoolValue(true),
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// apply sets the parameters of plan that differ from state, or all the set ones when state is nil, reclusters when
+// the cluster name changed and reads back the cluster name if it was left to the cluster.
+func (r *AerospikeConfigCluster) apply(ctx context.Context, plan, state *AerospikeConfigClusterModel) diag.Diagnostics {
+	params := clusterChanges(plan, state)
+
+	_, diags := r.asConn.setConfig(ctx, r.asConn.infoPolicy, "service", "", "", params, func(param string) path.Path {
+		if param == "cluster-name" {
+			return path.Root("cluster_name")
+		}
+		return path.Root("parameters").AtMapKey(param)
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	if _, ok := params["cluster-name"]; ok && plan.Recluster.ValueBool() {
+		diags.Append(r.recluster(ctx, plan)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	diags.Append(r.read(ctx, plan)...)
+
+	return diags
+}
+
+// recluster asks the principal to recluster and waits for the nodes to agree on a cluster key.
+func (r *AerospikeConfigCluster) recluster(ctx context.Context, data *AerospikeConfigClusterModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	responses, err := r.asConn.infoAllNodes(ctx, "recluster:")
+	if err != nil {
+		diags.AddError("Error reclustering", err.Error())
+		return diags
+	}
+	for _, node := range sortedKeys(responses) {
+		// only the principal acts on the command, the other nodes ignore it
+		res := strings.TrimSpace(responses[node])
+		if res != "ok" && res != "ignored-by-non-principal" {
+			diags.AddError("Error reclustering", fmt.Sprintf("Node %s returned %s for recluster", node, res))
+			return diags
+		}
+	}
+
+	timeout := int64(defaultReclusterTimeout)
+	if !data.Recluster_timeout.IsNull() {
+		timeout = data.Recluster_timeout.ValueInt64()
+	}
+	if err := r.asConn.waitForClusterStable(ctx, r.asConn.infoPolicy, true, time.Duration(timeout)*time.Second, clusterStablePollInterval); err != nil {
+		// cluster_name is already set on every node. The state isn't saved, so the next apply reclusters again
+		diags.AddError("Cluster did not form",
+			fmt.Sprintf("The nodes did not agree on a cluster key within %d seconds: %s", timeout, err))
+	}
+
+	tflog.Trace(ctx, "reclustered the cluster")
+
+	return diags
+}
+
+// read refreshes the cluster name and the managed parameters from get-config.
+func (r *AerospikeConfigCluster) read(ctx context.Context, data *AerospikeConfigClusterModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	command := getConfigCommand("service", "", "")
+	res, err := r.asConn.infoAnyNode(ctx, command)
+	if err != nil {
+		diags.AddError("Error reading cluster configuration", err.Error())
+		return diags
+	}
+	if msg, ok := infoErrorMessage(res); ok {
+		diags.AddError("Error reading cluster configuration", command+" returned "+msg)
+		return diags
+	}
+	current := parseInfoParams(res)
+
+	// the server reports "null" when the cluster has no name
+	if name, ok := current["cluster-name"]; ok && name != "null" {
+		data.Cluster_name = types.StringValue(name)
+	} else {
+		data.Cluster_name = types.StringNull()
+	}
+
+	for k := range data.Parameters {
+		if value, ok := current[k]; ok {
+			data.Parameters[k] = types.StringValue(value)
+		}
+	}
+
+	return diags
+}
+
+// clusterChanges returns the service context parameters of plan that differ from state, or all the set ones when
+// state is nil. A cluster name left to the cluster is unknown in the plan and isn't sent.
+func clusterChanges(plan, state *AerospikeConfigClusterModel) map[string]string {
+	params := make(map[string]string)
+
+	if !plan.Cluster_name.IsNull() && !plan.Cluster_name.IsUnknown() && (state == nil || !plan.Cluster_name.Equal(state.Cluster_name)) {
+		params["cluster-name"] = plan.Cluster_name.ValueString()
+	}
+	for k, v := range plan.Parameters {
+		if state != nil {
+			if stateValue, ok := state.Parameters[k]; ok && v.Equal(stateValue) {
+				continue
+			}
+		}
+		params[k] = v.ValueString()
+	}
+
+	return params
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeConfigCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_config_cluster" "test" {
+  parameters = {
+    "min-cluster-size" = "1"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config_cluster.test", "parameters.min-cluster-size", "1"),
+					resource.TestCheckResourceAttr("aerospike_config_cluster.test", "recluster", "true"),
+				),
+			},
+			{
+				ResourceName:            "aerospike_config_cluster.test",
+				ImportState:             true,
+				ImportStateId:           "cluster",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"parameters"},
+			},
+		},
+	})
+}
+
+func TestClusterChanges(t *testing.T) {
+	state := AerospikeConfigClusterModel{
+		Cluster_name: types.StringValue("old"),
+		Parameters:   map[string]types.String{"min-cluster-size": types.StringValue("3")},
+	}
+	plan := AerospikeConfigClusterModel{
+		Cluster_name: types.StringValue("new"),
+		Parameters: map[string]types.String{
+			"min-cluster-size":   types.StringValue("3"),
+			"migrate-fill-delay": types.StringValue("600"),
+		},
+	}
+
+	if got, want := clusterChanges(&plan, &state), map[string]string{"cluster-name": "new", "migrate-fill-delay": "600"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterChanges() = %v, want %v", got, want)
+	}
+
+	// on create a cluster name left to the cluster isn't sent
+	plan.Cluster_name = types.StringUnknown()
+	want := map[string]string{"min-cluster-size": "3", "migrate-fill-delay": "600"}
+	if got := clusterChanges(&plan, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterChanges() = %v, want %v", got, want)
+	}
+}
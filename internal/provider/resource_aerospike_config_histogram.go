@@ -0,0 +1,363 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeConfigHistogram{}
+var _ resource.ResourceWithImportState = &AerospikeConfigHistogram{}
+var _ resource.ResourceWithModifyPlan = &AerospikeConfigHistogram{}
+
+// histogramParameters maps the resource attributes to the namespace benchmark parameters.
+var histogramParameters = map[string]string{
+	"read":      "enable-benchmarks-read",
+	"write":     "enable-benchmarks-write",
+	"udf":       "enable-benchmarks-udf",
+	"batch_sub": "enable-benchmarks-batch-sub",
+	"ops_sub":   "enable-benchmarks-ops-sub",
+	"udf_sub":   "enable-benchmarks-udf-sub",
+	"storage":   "enable-benchmarks-storage",
+}
+
+func NewAerospikeConfigHistogram() resource.Resource {
+	return &AerospikeConfigHistogram{}
+}
+
+// AerospikeConfigHistogram defines the resource implementation.
+type AerospikeConfigHistogram struct {
+	asConn *asConnection
+}
+
+// AerospikeConfigHistogramModel describes the resource data model.
+type AerospikeConfigHistogramModel struct {
+	Namespace types.String `tfsdk:"namespace"`
+	Read      types.Bool   `tfsdk:"read"`
+	Write     types.Bool   `tfsdk:"write"`
+	Udf       types.Bool   `tfsdk:"udf"`
+	Batch_sub types.Bool   `tfsdk:"batch_sub"`
+	Ops_sub   types.Bool   `tfsdk:"ops_sub"`
+	Udf_sub   types.Bool   `tfsdk:"udf_sub"`
+	Storage   types.Bool   `tfsdk:"storage"`
+
+	Planned_commands types.List `tfsdk:"planned_commands"`
+}
+
+// values returns the model's benchmark settings keyed by attribute name.
+func (m *AerospikeConfigHistogramModel) values() map[string]*types.Bool {
+	return map[string]*types.Bool{
+		"read":      &m.Read,
+		"write":     &m.Write,
+		"udf":       &m.Udf,
+		"batch_sub": &m.Batch_sub,
+		"ops_sub":   &m.Ops_sub,
+		"udf_sub":   &m.Udf_sub,
+		"storage":   &m.Storage,
+	}
+}
+
+func (r *AerospikeConfigHistogram) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_histogram"
+}
+
+func (r *AerospikeConfigHistogram) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := map[string]schema.Attribute{
+		"namespace": schema.StringAttribute{
+			Description: "Namespace",
+			Required:    true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+	}
+	for attr, param := range histogramParameters {
+		attributes[attr] = schema.BoolAttribute{
+			Description: "Enable the " + param + " histograms",
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(false),
+		}
+	}
+
+	attributes["planned_commands"] = schema.ListAttribute{
+		Description: "set-config commands issued by the apply, shown in the plan so they can be reviewed before approving",
+		Computed:    true,
+		ElementType: types.StringType,
+	}
+
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Benchmark histograms of a namespace. Destroying the resource disables all the histograms",
+
+		Attributes: attributes,
+	}
+}
+
+func (r *AerospikeConfigHistogram) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_config_histogram is not supported",
+			"aerospike_config_histogram uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+// ModifyPlan shows the set-config commands in the plan when the plan changes the histograms.
+func (r *AerospikeConfigHistogram) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to show on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan AerospikeConfigHistogramModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.Planned_commands.IsUnknown() || plan.Namespace.IsUnknown() {
+		return
+	}
+	for _, v := range plan.values() {
+		if v.IsUnknown() {
+			return
+		}
+	}
+
+	// a new resource is checked against the cluster's namespaces before anything is sent
+	if req.State.Raw.IsNull() && r.asConn != nil {
+		resp.Diagnostics.Append(r.asConn.checkNamespace(ctx, path.Root("namespace"), plan.Namespace.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var prior *AerospikeConfigHistogramModel
+	if !req.State.Raw.IsNull() {
+		prior = &AerospikeConfigHistogramModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	commands := histogramCommands(plan.Namespace.ValueString(), histogramChanges(&plan, prior))
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("planned_commands"), stringList(commands))...)
+}
+
+func (r *AerospikeConfigHistogram) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_config_histogram")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigHistogramModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := histogramChanges(&data, nil)
+	resp.Diagnostics.Append(r.setHistograms(ctx, data.Namespace.ValueString(), params)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Planned_commands.IsUnknown() {
+		data.Planned_commands = stringList(histogramCommands(data.Namespace.ValueString(), params))
+	}
+
+	tflog.Trace(ctx, "set benchmark histograms for namespace "+data.Namespace.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigHistogram) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeConfigHistogramModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	command := getConfigCommand("namespace", data.Namespace.ValueString(), "")
+	res, err := r.asConn.infoAnyNode(ctx, command)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading configuration", err.Error())
+		return
+	}
+	if msg, ok := infoErrorMessage(res); ok {
+		exists, err := r.asConn.namespaceExists(ctx, data.Namespace.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading configuration", err.Error())
+			return
+		}
+		if exists {
+			resp.Diagnostics.AddError("Error reading configuration", command+" returned "+msg)
+			return
+		}
+		resp.Diagnostics.AddWarning("Namespace removed",
+			"Namespace "+data.Namespace.ValueString()+" no longer exists in the cluster, removing its histograms from the state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	current := parseInfoParams(res)
+
+	for attr, v := range data.values() {
+		if value, ok := current[histogramParameters[attr]]; ok {
+			*v = types.BoolValue(value == "true")
+		} else if v.IsNull() {
+			// the server doesn't have this histogram
+			*v = types.BoolValue(false)
+		}
+	}
+
+	tflog.Trace(ctx, "read benchmark histograms for namespace "+data.Namespace.ValueString())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigHistogram) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_config_histogram")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeConfigHistogramModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := histogramChanges(&plan, &state)
+	resp.Diagnostics.Append(r.setHistograms(ctx, plan.Namespace.ValueString(), params)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Planned_commands.IsUnknown() {
+		plan.Planned_commands = stringList(histogramCommands(plan.Namespace.ValueString(), params))
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeConfigHistogram) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_config_histogram")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigHistogramModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := make(map[string]string)
+	for attr, v := range data.values() {
+		if v.ValueBool() {
+			params[histogramParameters[attr]] = "false"
+		}
+	}
+
+	resp.Diagnostics.Append(r.setHistograms(ctx, data.Namespace.ValueString(), params)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "disabled benchmark histograms for namespace "+data.Namespace.ValueString())
+}
+
+// ImportState expects the namespace name as the id.
+func (r *AerospikeConfigHistogram) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("namespace"), req, resp)
+}
+
+// histogramChanges returns the benchmark parameters of plan that differ from state, or all of them when state is nil.
+func histogramChanges(plan, state *AerospikeConfigHistogramModel) map[string]string {
+	params := make(map[string]string)
+
+	for attr, v := range plan.values() {
+		if state == nil || !v.Equal(*state.values()[attr]) {
+			params[histogramParameters[attr]] = strconv.FormatBool(v.ValueBool())
+		}
+	}
+
+	return params
+}
+
+// histogramCommands returns the set-config commands that set params, sorted by parameter.
+func histogramCommands(namespace string, params map[string]string) []string {
+	commands := make([]string, 0, len(params))
+	for _, k := range sortedKeys(params) {
+		commands = append(commands, setConfigCommand("namespace", namespace, "", k, params[k]))
+	}
+
+	return commands
+}
+
+// setHistograms sets the benchmark parameters on all nodes and reports mismatches on the matching attribute.
+func (r *AerospikeConfigHistogram) setHistograms(ctx context.Context, namespace string, params map[string]string) diag.Diagnostics {
+	attrs := make(map[string]string, len(histogramParameters))
+	for attr, param := range histogramParameters {
+		attrs[param] = attr
+	}
+
+	_, diags := r.asConn.setConfig(ctx, r.asConn.infoPolicy, "namespace", namespace, "", params, func(param string) path.Path {
+		return path.Root(attrs[param])
+	})
+
+	return diags
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeConfigHistogram(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeConfigHistogramConfig("true", "false"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "read", "true"),
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "write", "false"),
+				),
+			},
+			// update
+			{
+				Config: testAccAerospikeConfigHistogramConfig("false", "true"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "read", "false"),
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "write", "true"),
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "planned_commands.#", "2"),
+					resource.TestCheckResourceAttr("aerospike_config_histogram.test", "planned_commands.0",
+						"set-config:context=namespace;id=aerospike;enable-benchmarks-read=false"),
+				),
+			},
+			// import
+			{
+				ResourceName:                         "aerospike_config_histogram.test",
+				ImportState:                          true,
+				ImportStateId:                        "aerospike",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "namespace",
+				// planned_commands are only known from a plan
+				ImportStateVerifyIgnore: []string{"planned_commands"},
+			},
+		},
+	})
+}
+
+func testAccAerospikeConfigHistogramConfig(read, write string) string {
+	return fmt.Sprintf(`
+resource "aerospike_config_histogram" "test" {
+  namespace = "aerospike"
+  read      = %s
+  write     = %s
+}`, read, write)
+}
@@ -0,0 +1,250 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeConfigConfig("aerospike", "120"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.test", "context", "namespace"),
+					resource.TestCheckResourceAttr("aerospike_config.test", "parameters.nsup-period", "120"),
+				),
+			},
+			// update parameter
+			{
+				Config: testAccAerospikeConfigConfig("aerospike", "60"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.test", "parameters.nsup-period", "60"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAerospikeConfigConfig(namespace string, nsupPeriod string) string {
+	return fmt.Sprintf(`
+resource "aerospike_config" "test" {
+  context   = "namespace"
+  namespace = "%[1]s"
+  parameters = {
+    "nsup-period" = "%[2]s"
+  }
+}`, namespace, nsupPeriod)
+}
+
+func TestAccAerospikeConfigDryRun(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// the command is recorded but not sent, so the refresh after the apply finds the parameter changed
+			{
+				Config: `
+resource "aerospike_config" "test" {
+  context   = "namespace"
+  namespace = "aerospike"
+  dry_run   = true
+  parameters = {
+    "nsup-period" = "1234"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.test", "planned_commands.#", "1"),
+					resource.TestCheckResourceAttr("aerospike_config.test", "planned_commands.0",
+						"set-config:context=namespace;id=aerospike;nsup-period=1234"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAerospikeConfigRestoreOnDestroy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAerospikeConfigRestoreConfig(`"migrate-threads" = "2"
+    "proto-fd-idle-ms" = "70000"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.restore", "parameters.migrate-threads", "2"),
+				),
+			},
+			// removing a parameter restores it, destroying the resource restores the rest
+			{
+				Config: testAccAerospikeConfigRestoreConfig(`"migrate-threads" = "3"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.restore", "parameters.migrate-threads", "3"),
+					resource.TestCheckNoResourceAttr("aerospike_config.restore", "parameters.proto-fd-idle-ms"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAerospikeConfigRestoreConfig(parameters string) string {
+	return fmt.Sprintf(`
+resource "aerospike_config" "restore" {
+  context            = "service"
+  restore_on_destroy = true
+  parameters = {
+    %s
+  }
+}`, parameters)
+}
+
+func TestAccAerospikeConfigNetwork(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_config" "heartbeat" {
+  context = "network"
+  parameters = {
+    "heartbeat.interval" = "150"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_config.heartbeat", "parameters.heartbeat.interval", "150"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAerospikeConfigUnknownNamespace(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_config" "missing" {
+  context   = "namespace"
+  namespace = "no-such-namespace"
+  parameters = {
+    "default-ttl" = "0"
+  }
+}`,
+				ExpectError: regexp.MustCompile(`Available namespaces: .*aerospike`),
+			},
+		},
+	})
+}
+
+func TestCompareNodeParameters(t *testing.T) {
+	managed := map[string]types.String{
+		"migrate-threads":    types.StringValue("2"),
+		"proto-fd-max":       types.StringValue("15000"),
+		"not-reported-param": types.StringValue("x"),
+	}
+	responses := map[string]string{
+		"BB9020011AC4202": "migrate-threads=2;proto-fd-max=15000",
+		"BB9030011AC4202": "migrate-threads=4;proto-fd-max=15000",
+		"BB9040011AC4202": "migrate-threads=2;proto-fd-max=15000",
+	}
+
+	params, nodes, diags := compareNodeParameters(managed, responses)
+
+	if want := []string{"BB9030011AC4202"}; !reflect.DeepEqual(nodes, want) {
+		t.Errorf("inconsistent nodes = %v, want %v", nodes, want)
+	}
+	if got := params["migrate-threads"].ValueString(); got != "4" {
+		t.Errorf("migrate-threads = %q, want the drifted value \"4\"", got)
+	}
+	if got := params["proto-fd-max"].ValueString(); got != "15000" {
+		t.Errorf("proto-fd-max = %q, want \"15000\"", got)
+	}
+	if got := params["not-reported-param"].ValueString(); got != "x" {
+		t.Errorf("not-reported-param = %q, want the managed value \"x\"", got)
+	}
+	if diags.WarningsCount() != 1 {
+		t.Errorf("got %d warnings, want 1", diags.WarningsCount())
+	}
+}
+
+func TestCheckNamespaceTTL(t *testing.T) {
+	cases := []struct {
+		name    string
+		params  map[string]types.String
+		current map[string]string
+		wantErr bool
+	}{
+		{"nsup disabled", map[string]types.String{"default-ttl": types.StringValue("3600")}, map[string]string{"nsup-period": "0"}, true},
+		{"nsup enabled", map[string]types.String{"default-ttl": types.StringValue("3600")}, map[string]string{"nsup-period": "120"}, false},
+		{"nsup enabled in plan", map[string]types.String{"default-ttl": types.StringValue("3600"), "nsup-period": types.StringValue("120")}, map[string]string{"nsup-period": "0"}, false},
+		{"allowed without nsup", map[string]types.String{"default-ttl": types.StringValue("3600")}, map[string]string{"nsup-period": "0", "allow-ttl-without-nsup": "true"}, false},
+		{"no ttl", map[string]types.String{"default-ttl": types.StringValue("0")}, map[string]string{"nsup-period": "0"}, false},
+	}
+
+	for _, c := range cases {
+		if got := checkNamespaceTTL(c.params, c.current).HasError(); got != c.wantErr {
+			t.Errorf("%s: checkNamespaceTTL() error = %v, want %v", c.name, got, c.wantErr)
+		}
+	}
+}
+
+func TestPlannedConfigCommands(t *testing.T) {
+	data := AerospikeConfigModel{
+		Context: types.StringValue("service"),
+		Parameters: map[string]types.String{
+			"migrate-threads":  types.StringValue("4"),
+			"proto-fd-max":     types.StringValue("20000"),
+			"tls-key-password": types.StringValue("secret"),
+		},
+	}
+	prior := map[string]types.String{
+		"migrate-threads":  types.StringValue("4"),
+		"proto-fd-max":     types.StringValue("15000"),
+		"proto-fd-idle-ms": types.StringValue("70000"),
+	}
+
+	got := plannedConfigCommands(data, prior, map[string]string{"proto-fd-idle-ms": "60000"})
+	want := []string{
+		"set-config:context=service;proto-fd-max=20000",
+		"set-config:context=service;tls-key-password=" + redactedValue,
+		"set-config:context=service;proto-fd-idle-ms=60000",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plannedConfigCommands() = %v, want %v", got, want)
+	}
+}
+
+func TestDryRunWarning(t *testing.T) {
+	data := AerospikeConfigModel{
+		Dry_run:          types.BoolValue(true),
+		Planned_commands: stringList([]string{"set-config:context=service;proto-fd-max=20000"}),
+	}
+
+	diags := dryRunWarning(context.Background(), data)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "proto-fd-max=20000") {
+		t.Errorf("dryRunWarning() = %v, want a warning with the planned command", diags)
+	}
+
+	data.Dry_run = types.BoolNull()
+	if diags := dryRunWarning(context.Background(), data); len(diags) != 0 {
+		t.Errorf("dryRunWarning() = %v, want no diagnostics without dry_run", diags)
+	}
+}
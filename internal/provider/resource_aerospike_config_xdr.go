@@ -0,0 +1,349 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strconv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeConfigXdr{}
+var _ resource.ResourceWithValidateConfig = &AerospikeConfigXdr{}
+var _ resource.ResourceWithImportState = &AerospikeConfigXdr{}
+
+// xdrTypedParameters maps the typed attributes of aerospike_config_xdr to their xdr context parameters.
+var xdrTypedParameters = map[string]string{
+	"src_id":       "src-id",
+	"trace_sample": "trace-sample",
+}
+
+func NewAerospikeConfigXdr() resource.Resource {
+	return &AerospikeConfigXdr{}
+}
+
+// AerospikeConfigXdr defines the resource implementation.
+type AerospikeConfigXdr struct {
+	asConn *asConnection
+}
+
+// AerospikeConfigXdrModel describes the resource data model.
+type AerospikeConfigXdrModel struct {
+	Src_id       types.Int64             `tfsdk:"src_id"`
+	Trace_sample types.Int64             `tfsdk:"trace_sample"`
+	Parameters   map[string]types.String `tfsdk:"parameters"`
+}
+
+// values returns the model's typed parameters keyed by attribute name.
+func (m *AerospikeConfigXdrModel) values() map[string]*types.Int64 {
+	return map[string]*types.Int64{
+		"src_id":       &m.Src_id,
+		"trace_sample": &m.Trace_sample,
+	}
+}
+
+func (r *AerospikeConfigXdr) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_xdr"
+}
+
+func (r *AerospikeConfigXdr) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Cluster wide XDR settings that aren't tied to a datacenter or namespace. Only one instance should be defined per cluster. " +
+			"Destroying the resource leaves the settings at their current values",
+
+		Attributes: map[string]schema.Attribute{
+			"src_id": schema.Int64Attribute{
+				Description: "Id of this cluster in the XDR topology, used to stop records from being shipped back to where they came from. " +
+					"Read from the cluster when not set",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 255),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"trace_sample": schema.Int64Attribute{
+				Description: "Trace every Nth shipped record in the log, 0 to disable tracing. Read from the cluster when not set",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"parameters": schema.MapAttribute{
+				Description: "Other dynamic xdr context parameters, as they are named in set-config",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *AerospikeConfigXdr) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var parameters types.Map
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("parameters"), &parameters)...)
+
+	if resp.Diagnostics.HasError() || parameters.IsNull() || parameters.IsUnknown() {
+		return
+	}
+
+	for k := range parameters.Elements() {
+		for attr, param := range xdrTypedParameters {
+			if k == param {
+				resp.Diagnostics.AddAttributeError(path.Root("parameters").AtMapKey(k), "Invalid parameter",
+					fmt.Sprintf("%s is managed with the %s attribute", param, attr))
+			}
+		}
+		if k == "dc" || k == "namespace" {
+			resp.Diagnostics.AddAttributeError(path.Root("parameters").AtMapKey(k), "Invalid parameter",
+				"aerospike_config_xdr manages the cluster wide settings. Use aerospike_config with context = \"xdr\" for datacenter and namespace settings")
+		}
+	}
+}
+
+func (r *AerospikeConfigXdr) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_config_xdr is not supported",
+			"aerospike_config_xdr uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	if !asConn.enterprise {
+		resp.Diagnostics.AddError("Enterprise Edition required",
+			"aerospike_config_xdr requires Enterprise Edition. The cluster runs Community Edition")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeConfigXdr) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_config_xdr")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeConfigXdrModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setXdr(ctx, xdrChanges(&data, nil))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// read back the typed parameters that weren't set
+	resp.Diagnostics.Append(r.read(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "set cluster wide xdr configuration")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigXdr) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeConfigXdrModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read cluster wide xdr configuration")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeConfigXdr) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_config_xdr")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeConfigXdrModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setXdr(ctx, xdrChanges(&plan, &state))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeConfigXdr) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_config_xdr")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Dynamic configuration can't be unset, the parameters stay at their current values
+	tflog.Trace(ctx, "removed cluster wide xdr configuration from state, server values are unchanged")
+}
+
+// ImportState ignores the id, there is only one set of cluster wide xdr settings.
+func (r *AerospikeConfigXdr) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	data := AerospikeConfigXdrModel{
+		Src_id:       types.Int64Unknown(),
+		Trace_sample: types.Int64Unknown(),
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// read refreshes the typed parameters and the managed parameters from get-config.
+func (r *AerospikeConfigXdr) read(ctx context.Context, data *AerospikeConfigXdrModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	command := getConfigCommand("xdr", "", "")
+	res, err := r.asConn.infoAnyNode(ctx, command)
+	if err != nil {
+		diags.AddError("Error reading xdr configuration", err.Error())
+		return diags
+	}
+	if msg, ok := infoErrorMessage(res); ok {
+		diags.AddError("Error reading xdr configuration", command+" returned "+msg)
+		return diags
+	}
+	current := parseInfoParams(res)
+
+	for attr, v := range data.values() {
+		value, ok := current[xdrTypedParameters[attr]]
+		if !ok {
+			if v.IsUnknown() {
+				*v = types.Int64Null()
+			}
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			diags.AddAttributeError(path.Root(attr), "Unexpected value",
+				fmt.Sprintf("%s returned %s=%q", command, xdrTypedParameters[attr], value))
+			continue
+		}
+		*v = types.Int64Value(n)
+	}
+
+	for k := range data.Parameters {
+		if value, ok := current[k]; ok {
+			data.Parameters[k] = types.StringValue(value)
+		}
+	}
+
+	return diags
+}
+
+// setXdr sets the xdr context parameters on all nodes and reports mismatches on the matching attribute.
+func (r *AerospikeConfigXdr) setXdr(ctx context.Context, params map[string]string) diag.Diagnostics {
+	attrs := make(map[string]string, len(xdrTypedParameters))
+	for attr, param := range xdrTypedParameters {
+		attrs[param] = attr
+	}
+
+	_, diags := r.asConn.setConfig(ctx, r.asConn.infoPolicy, "xdr", "", "", params, func(param string) path.Path {
+		if attr, ok := attrs[param]; ok {
+			return path.Root(attr)
+		}
+		return path.Root("parameters").AtMapKey(param)
+	})
+
+	return diags
+}
+
+// xdrChanges returns the xdr context parameters of plan that differ from state, or all the set ones when state is
+// nil. Typed parameters left to the cluster are unknown in the plan and aren't sent.
+func xdrChanges(plan, state *AerospikeConfigXdrModel) map[string]string {
+	params := make(map[string]string)
+
+	for attr, v := range plan.values() {
+		if v.IsNull() || v.IsUnknown() || (state != nil && v.Equal(*state.values()[attr])) {
+			continue
+		}
+		params[xdrTypedParameters[attr]] = strconv.FormatInt(v.ValueInt64(), 10)
+	}
+	for k, v := range plan.Parameters {
+		if state != nil {
+			if stateValue, ok := state.Parameters[k]; ok && v.Equal(stateValue) {
+				continue
+			}
+		}
+		params[k] = v.ValueString()
+	}
+
+	return params
+}
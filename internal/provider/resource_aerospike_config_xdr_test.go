@@ -0,0 +1,38 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestXdrChanges(t *testing.T) {
+	state := AerospikeConfigXdrModel{
+		Src_id:       types.Int64Value(1),
+		Trace_sample: types.Int64Value(0),
+		Parameters:   map[string]types.String{"max-throughput": types.StringValue("100000")},
+	}
+	plan := AerospikeConfigXdrModel{
+		Src_id:       types.Int64Value(2),
+		Trace_sample: types.Int64Value(0),
+		Parameters: map[string]types.String{
+			"max-throughput": types.StringValue("100000"),
+			"period-ms":      types.StringValue("50"),
+		},
+	}
+
+	if got, want := xdrChanges(&plan, &state), map[string]string{"src-id": "2", "period-ms": "50"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("xdrChanges() = %v, want %v", got, want)
+	}
+
+	// on create the typed parameters left to the cluster aren't sent
+	plan.Trace_sample = types.Int64Unknown()
+	want := map[string]string{"src-id": "2", "max-throughput": "100000", "period-ms": "50"}
+	if got := xdrChanges(&plan, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("xdrChanges() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeInfoCommand{}
+
+func NewAerospikeInfoCommand() resource.Resource {
+	return &AerospikeInfoCommand{}
+}
+
+// AerospikeInfoCommand defines the resource implementation.
+type AerospikeInfoCommand struct {
+	asConn *asConnection
+}
+
+// AerospikeInfoCommandModel describes the resource data model.
+type AerospikeInfoCommandModel struct {
+	Commands  []types.String          `tfsdk:"commands"`
+	All_nodes types.Bool              `tfsdk:"all_nodes"`
+	Triggers  map[string]types.String `tfsdk:"triggers"`
+	Responses types.List              `tfsdk:"responses"`
+}
+
+// infoCommandResponseType is the object type of the responses of aerospike_info_command.
+var infoCommandResponseType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"command":  types.StringType,
+	"node":     types.StringType,
+	"response": types.StringType,
+}}
+
+// destructiveInfoCommands are the info commands that are blocked when allow_destructive_operations is false.
+var destructiveInfoCommands = []string{"truncate", "truncate-namespace", "udf-remove", "sindex-delete"}
+
+// infoCommandName returns the name of an info command, the part before its parameters.
+func infoCommandName(command string) string {
+	name, _, _ := strings.Cut(command, ":")
+
+	return strings.TrimSpace(name)
+}
+
+type AerospikeInfoCommandResponse struct {
+	Command  types.String `tfsdk:"command"`
+	Node     types.String `tfsdk:"node"`
+	Response types.String `tfsdk:"response"`
+}
+
+func (r *AerospikeInfoCommand) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_info_command"
+}
+
+func (r *AerospikeInfoCommand) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Runs info commands when created, and again when commands, all_nodes or triggers change. " +
+			"Covers operational commands the provider doesn't model. Destroying the resource doesn't run anything",
+
+		Attributes: map[string]schema.Attribute{
+			"commands": schema.ListAttribute{
+				Description: "Info commands to run, in order, e.g. \"recluster:\" or \"set-config:context=service;migrate-threads=2\". " +
+					"The run stops at the first command the server rejects. Sensitive, since set-config commands may carry credentials, " +
+					"which terraform still stores in the state",
+				Required:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"all_nodes": schema.BoolAttribute{
+				Description: "Run the commands on every node instead of on one node. Defaults to false",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values that run the commands again when they change",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"responses": schema.ListNestedAttribute{
+				Description: "Responses of the commands, in the order they ran",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"command": schema.StringAttribute{
+							Description: "Command, with the values of parameters that may hold credentials redacted",
+							Computed:    true,
+						},
+						"node": schema.StringAttribute{
+							Description: "Node that responded, null when all_nodes is false",
+							Computed:    true,
+						},
+						"response": schema.StringAttribute{
+							Description: "Response of the node",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeInfoCommand) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_info_command is not supported",
+			"aerospike_info_command uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeInfoCommand) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_info_command")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeInfoCommandModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// check every command before running any, so a blocked command doesn't leave the run half done
+	for _, c := range data.Commands {
+		if sliceutil.Contains(destructiveInfoCommands, infoCommandName(c.ValueString())) {
+			resp.Diagnostics.Append(r.asConn.checkDestructive("Info command " + infoCommandName(c.ValueString()))...)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	results := make([]AerospikeInfoCommandResponse, 0, len(data.Commands))
+	for _, c := range data.Commands {
+		command := c.ValueString()
+
+		responses := make(map[string]string)
+		if data.All_nodes.ValueBool() {
+			var err error
+			responses, err = r.asConn.infoAllNodes(ctx, command)
+			if err != nil {
+				resp.Diagnostics.AddError("Error running info command", err.Error())
+				return
+			}
+		} else {
+			res, err := r.asConn.infoAnyNode(ctx, command)
+			if err != nil {
+				resp.Diagnostics.AddError("Error running info command", err.Error())
+				return
+			}
+			responses[""] = res
+		}
+
+		for _, node := range sortedKeys(responses) {
+			if msg, ok := infoErrorMessage(responses[node]); ok {
+				resp.Diagnostics.AddError("Info command failed",
+					fmt.Sprintf("%s returned %s", redactInfoCommand(command), msg))
+				return
+			}
+
+			nodeValue := types.StringValue(node)
+			if node == "" {
+				nodeValue = types.StringNull()
+			}
+			results = append(results, AerospikeInfoCommandResponse{
+				Command:  types.StringValue(redactInfoCommand(command)),
+				Node:     nodeValue,
+				Response: types.StringValue(responses[node]),
+			})
+		}
+
+		tflog.Trace(ctx, "ran "+redactInfoCommand(command))
+	}
+
+	var diags diag.Diagnostics
+	data.Responses, diags = types.ListValueFrom(ctx, infoCommandResponseType, results)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeInfoCommand) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The responses are those of the last run, there is nothing to refresh
+}
+
+func (r *AerospikeInfoCommand) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_info_command")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every attribute requires replacement, so there is nothing to update in place
+	resp.Diagnostics.AddError("Unexpected update", "aerospike_info_command is replaced on every change. Please report this issue to the provider developers.")
+}
+
+func (r *AerospikeInfoCommand) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Nothing to undo, the resource is removed from state. Nothing is sent to the cluster, so read_only doesn't apply
+	tflog.Trace(ctx, "removed info command from state")
+}
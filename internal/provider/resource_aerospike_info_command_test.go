@@ -0,0 +1,72 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeInfoCommand(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAerospikeInfoCommandConfig(false, "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_info_command.test", "responses.#", "2"),
+					resource.TestCheckResourceAttr("aerospike_info_command.test", "responses.0.command", "build"),
+					resource.TestCheckResourceAttrSet("aerospike_info_command.test", "responses.0.response"),
+					resource.TestCheckNoResourceAttr("aerospike_info_command.test", "responses.0.node"),
+				),
+			},
+			// a trigger change runs the commands again, on every node
+			{
+				Config: testAccAerospikeInfoCommandConfig(true, "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("aerospike_info_command.test", "responses.0.node"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAerospikeInfoCommandConfig(allNodes bool, trigger string) string {
+	return fmt.Sprintf(`
+resource "aerospike_info_command" "test" {
+  commands  = ["build", "edition"]
+  all_nodes = %t
+  triggers = {
+    run = "%s"
+  }
+}`, allNodes, trigger)
+}
+
+func TestInfoCommandName(t *testing.T) {
+	for command, want := range map[string]string{
+		"truncate-namespace:namespace=test": "truncate-namespace",
+		" udf-remove:filename=x.lua":        "udf-remove",
+		"recluster:":                        "recluster",
+		"statistics":                        "statistics",
+	} {
+		if got := infoCommandName(command); got != want {
+			t.Errorf("infoCommandName(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestCheckDestructive(t *testing.T) {
+	conn := newFakeConnection(newFakeClient(), "admin")
+	if diags := conn.checkDestructive("Dropping user u1"); diags.HasError() {
+		t.Errorf("checkDestructive() = %v, want no errors by default", diags)
+	}
+
+	conn.blockDestructive = true
+	if diags := conn.checkDestructive("Dropping user u1"); !diags.HasError() {
+		t.Error("checkDestructive() returned no errors with allow_destructive_operations = false")
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeRecord{}
+var _ resource.ResourceWithModifyPlan = &AerospikeRecord{}
+var _ resource.ResourceWithImportState = &AerospikeRecord{}
+
+func NewAerospikeRecord() resource.Resource {
+	return &AerospikeRecord{}
+}
+
+// AerospikeRecord defines the resource implementation.
+type AerospikeRecord struct {
+	asConn *asConnection
+}
+
+// AerospikeRecordModel describes the resource data model.
+type AerospikeRecordModel struct {
+	Namespace types.String            `tfsdk:"namespace"`
+	Set       types.String            `tfsdk:"set"`
+	Key       types.String            `tfsdk:"key"`
+	Bins      map[string]types.String `tfsdk:"bins"`
+}
+
+func (r *AerospikeRecord) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_record"
+}
+
+func (r *AerospikeRecord) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Aerospike record with string bins, intended for small configuration records",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Namespace",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"set": schema.StringAttribute{
+				Description: "Set. Optional - if null the record is written outside of any set",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Description: "Record user key (string)",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bins": schema.MapAttribute{
+				Description: "Map of bin names to string values. Other bins of the record are left alone, but the managed bins must hold strings",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.LengthBetween(1, 15)),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeRecord) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	r.asConn = asConn
+}
+
+// ModifyPlan warns when the namespace is in stop-writes, before the apply fails to write the record.
+func (r *AerospikeRecord) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// deletes work in stop-writes, and nothing is written when the plan doesn't change the record
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || r.asConn == nil {
+		return
+	}
+
+	var namespace types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	if resp.Diagnostics.HasError() || namespace.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.asConn.warnStopWrites(ctx, path.Root("namespace"), namespace.ValueString())...)
+}
+
+func (r *AerospikeRecord) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_record")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRecordModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record key", err.Error())
+		return
+	}
+
+	bins := make(as.BinMap, len(data.Bins))
+	for k, v := range data.Bins {
+		bins[k] = v.ValueString()
+	}
+
+	wp := as.NewWritePolicy(0, 0)
+	wp.SendKey = true
+	wp.RecordExistsAction = as.CREATE_ONLY
+
+	err = r.asConn.getClient(ctx).Put(wp, key, bins)
+	if err != nil {
+		if err.Matches(astypes.KEY_EXISTS_ERROR) {
+			resp.Diagnostics.AddError("Record already exists",
+				"Record "+recordID(data)+" already exists. Import it to manage it with terraform")
+			return
+		}
+		resp.Diagnostics.AddError("Error writing record", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "created record "+recordID(data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRecord) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeRecordModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record key", err.Error())
+		return
+	}
+
+	record, err := r.asConn.getClient(ctx).Get(r.asConn.readPolicy, key)
+	if err != nil {
+		if err.Matches(astypes.KEY_NOT_FOUND_ERROR) {
+			tflog.Trace(ctx, "read record "+recordID(data)+" and it does not exist")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading record", err.Error())
+		return
+	}
+
+	// only the bins in the prior state are refreshed, all of them on import
+	bins, binsErr := managedBins(record.Bins, data.Bins)
+	if binsErr != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("bins"), "Unsupported bin type", "Record "+recordID(data)+": "+binsErr.Error())
+		return
+	}
+	data.Bins = bins
+
+	tflog.Trace(ctx, "read record "+recordID(data))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRecord) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_record")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeRecordModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := as.NewKey(plan.Namespace.ValueString(), plan.Set.ValueString(), plan.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record key", err.Error())
+		return
+	}
+
+	bins := make(as.BinMap, len(plan.Bins))
+	for k, v := range plan.Bins {
+		bins[k] = v.ValueString()
+	}
+	// a nil value removes the bin
+	for k := range state.Bins {
+		if _, ok := plan.Bins[k]; !ok {
+			bins[k] = nil
+		}
+	}
+
+	wp := as.NewWritePolicy(0, 0)
+	wp.SendKey = true
+
+	err = r.asConn.getClient(ctx).Put(wp, key, bins)
+	if err != nil {
+		resp.Diagnostics.AddError("Error writing record", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "updated record "+recordID(plan))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeRecord) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_record")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRecordModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid record key", err.Error())
+		return
+	}
+
+	_, err = r.asConn.getClient(ctx).Delete(nil, key)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting record", err.Error())
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "deleted record "+recordID(data))
+}
+
+// ImportState expects an id of the form namespace:set:key. The set may be empty.
+func (r *AerospikeRecord) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.SplitN(req.ID, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError("Invalid import id", "Expected namespace:set:key, got "+req.ID)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), parts[0])...)
+	if parts[1] != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("set"), parts[1])...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), parts[2])...)
+}
+
+// managedBins returns the bins of a record that are in managed, or all of them when managed is nil. Bins that
+// aren't strings are an error rather than converted, since writing the string back would change the bin's type.
+func managedBins(bins as.BinMap, managed map[string]types.String) (map[string]types.String, error) {
+	result := make(map[string]types.String, len(bins))
+	for _, name := range sortedKeys(bins) {
+		if _, ok := managed[name]; managed != nil && !ok {
+			continue
+		}
+		value, ok := bins[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("bin %s holds a %T, only string bins can be managed", name, bins[name])
+		}
+		result[name] = types.StringValue(value)
+	}
+
+	return result, nil
+}
+
+func recordID(data AerospikeRecordModel) string {
+	return data.Namespace.ValueString() + ":" + data.Set.ValueString() + ":" + data.Key.ValueString()
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeRecord(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeRecordConfig("testrecord1", "bin1 = \"a\", bin2 = \"b\""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_record.testrecord1", "key", "testrecord1"),
+					resource.TestCheckResourceAttr("aerospike_record.testrecord1", "bins.bin1", "a"),
+					resource.TestCheckResourceAttr("aerospike_record.testrecord1", "bins.bin2", "b"),
+				),
+			},
+			// update value and remove a bin
+			{
+				Config: testAccAerospikeRecordConfig("testrecord1", "bin1 = \"c\""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_record.testrecord1", "bins.%", "1"),
+					resource.TestCheckResourceAttr("aerospike_record.testrecord1", "bins.bin1", "c"),
+				),
+			},
+			// import
+			{
+				ResourceName:                         "aerospike_record.testrecord1",
+				ImportState:                          true,
+				ImportStateId:                        "aerospike:test:testrecord1",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "key",
+			},
+		},
+	})
+}
+
+func testAccAerospikeRecordConfig(key string, bins string) string {
+	return fmt.Sprintf(`
+resource "aerospike_record" "%[1]s" {
+  namespace = "aerospike"
+  set       = "test"
+  key       = "%[1]s"
+  bins      = { %[2]s }
+}`, key, bins)
+}
+
+// readRecord reads the record of data with the fake client and returns the refreshed state.
+func readRecord(t *testing.T, r *AerospikeRecord, data AerospikeRecordModel) (AerospikeRecordModel, *fwresource.ReadResponse) {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, &data); diags.HasError() {
+		t.Fatalf("setting the state: %v", diags)
+	}
+
+	resp := &fwresource.ReadResponse{State: state}
+	r.Read(ctx, fwresource.ReadRequest{State: state}, resp)
+
+	var got AerospikeRecordModel
+	if !resp.Diagnostics.HasError() {
+		resp.State.Get(ctx, &got)
+	}
+
+	return got, resp
+}
+
+func TestAerospikeRecordRead(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeRecord{asConn: newFakeConnection(client, "admin")}
+	key, _ := as.NewKey("test", "config", "r1")
+	_ = client.Put(nil, key, as.BinMap{"a": "x", "other": "y", "count": 5})
+
+	data := AerospikeRecordModel{
+		Namespace: types.StringValue("test"),
+		Set:       types.StringValue("config"),
+		Key:       types.StringValue("r1"),
+		Bins:      map[string]types.String{"a": types.StringValue("old")},
+	}
+
+	// bins terraform doesn't manage are left out
+	got, resp := readRecord(t, r, data)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() returned errors: %v", resp.Diagnostics)
+	}
+	if want := map[string]types.String{"a": types.StringValue("x")}; !reflect.DeepEqual(got.Bins, want) {
+		t.Errorf("Read() bins = %v, want %v", got.Bins, want)
+	}
+
+	// an int bin isn't converted to a string that the next update would write back
+	data.Bins = map[string]types.String{"count": types.StringValue("5")}
+	if _, resp := readRecord(t, r, data); !resp.Diagnostics.HasError() {
+		t.Errorf("Read() of an int bin succeeded, want an error")
+	}
+
+	// on import every bin is read
+	data.Bins = nil
+	if _, resp := readRecord(t, r, data); !resp.Diagnostics.HasError() {
+		t.Errorf("Read() of an imported record with an int bin succeeded, want an error")
+	}
+	_ = client.Put(nil, key, as.BinMap{"count": nil})
+	got, resp = readRecord(t, r, data)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() of an imported record returned errors: %v", resp.Diagnostics)
+	}
+	if want := map[string]types.String{"a": types.StringValue("x"), "other": types.StringValue("y")}; !reflect.DeepEqual(got.Bins, want) {
+		t.Errorf("Read() bins of an imported record = %v, want %v", got.Bins, want)
+	}
+}
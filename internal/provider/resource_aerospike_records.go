@@ -0,0 +1,437 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeRecords{}
+var _ resource.ResourceWithModifyPlan = &AerospikeRecords{}
+
+func NewAerospikeRecords() resource.Resource {
+	return &AerospikeRecords{}
+}
+
+// AerospikeRecords defines the resource implementation.
+type AerospikeRecords struct {
+	asConn *asConnection
+}
+
+// AerospikeRecordsModel describes the resource data model.
+type AerospikeRecordsModel struct {
+	Namespace   types.String                       `tfsdk:"namespace"`
+	Set         types.String                       `tfsdk:"set"`
+	Records     map[string]map[string]types.String `tfsdk:"records"`
+	Generations types.Map                          `tfsdk:"generations"`
+}
+
+func (r *AerospikeRecords) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_records"
+}
+
+func (r *AerospikeRecords) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "A collection of records with string bins in one set, written and read with batch commands. " +
+			"Intended for seeding reference data, where an aerospike_record per record doesn't scale",
+
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Description: "Namespace",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"set": schema.StringAttribute{
+				Description: "Set. Optional - if null the records are written outside of any set",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"records": schema.MapAttribute{
+				Description: "Map of record user keys (strings) to maps of bin names to string values, e.g. jsondecode(file(\"countries.json\")). " +
+					"Other bins of the records are left alone, but the managed bins must hold strings",
+				Required:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueMapsAre(
+						mapvalidator.SizeAtLeast(1),
+						mapvalidator.KeysAre(stringvalidator.LengthBetween(1, 15)),
+					),
+				},
+			},
+			"generations": schema.MapAttribute{
+				Description: "Generation of each record, found during the last refresh. A generation that changes without an apply " +
+					"means the record was written outside terraform",
+				Computed:    true,
+				ElementType: types.Int64Type,
+			},
+		},
+	}
+}
+
+func (r *AerospikeRecords) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	r.asConn = asConn
+}
+
+// ModifyPlan warns when the namespace is in stop-writes, before the apply fails to write the records.
+func (r *AerospikeRecords) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// deletes work in stop-writes, and nothing is written when the plan doesn't change the records
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) || r.asConn == nil {
+		return
+	}
+
+	var namespace types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	if resp.Diagnostics.HasError() || namespace.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.asConn.warnStopWrites(ctx, path.Root("namespace"), namespace.ValueString())...)
+}
+
+func (r *AerospikeRecords) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_records")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRecordsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	writes, _ := recordsChanges(data.Records, nil)
+	resp.Diagnostics.Append(r.write(ctx, data, writes, nil, map[string]bool{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.readGenerations(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("created %d records in %s", len(data.Records), recordsSetID(data)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRecords) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeRecordsModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records, generations, diags := r.read(ctx, data, sortedKeys(data.Records))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// records deleted outside terraform are dropped, so the plan writes them again
+	data.Records = records
+	data.Generations = generationsMap(generations)
+
+	tflog.Trace(ctx, fmt.Sprintf("read %d records in %s", len(records), recordsSetID(data)))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRecords) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_records")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeRecordsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// records new to the state are created, so records written outside terraform aren't overwritten
+	created := make(map[string]bool)
+	for k := range plan.Records {
+		if _, ok := state.Records[k]; !ok {
+			created[k] = true
+		}
+	}
+
+	writes, deletes := recordsChanges(plan.Records, state.Records)
+	resp.Diagnostics.Append(r.write(ctx, plan, writes, deletes, created)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.readGenerations(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("wrote %d and deleted %d records in %s", len(writes), len(deletes), recordsSetID(plan)))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeRecords) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_records")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRecordsModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.write(ctx, data, nil, sortedKeys(data.Records), nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, fmt.Sprintf("deleted %d records in %s", len(data.Records), recordsSetID(data)))
+}
+
+// write sends the writes and deletes in one batch. Records in create fail if they already exist.
+func (r *AerospikeRecords) write(ctx context.Context, data AerospikeRecordsModel, writes map[string]as.BinMap, deletes []string, create map[string]bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	batch := make([]as.BatchRecordIfc, 0, len(writes)+len(deletes))
+	for _, k := range sortedKeys(writes) {
+		key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), k)
+		if err != nil {
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Invalid record key", err.Error())
+			return diags
+		}
+
+		wp := as.NewBatchWritePolicy()
+		wp.SendKey = true
+		if create[k] {
+			wp.RecordExistsAction = as.CREATE_ONLY
+		}
+
+		ops := make([]*as.Operation, 0, len(writes[k]))
+		for _, bin := range sortedKeys(writes[k]) {
+			// a nil value removes the bin
+			ops = append(ops, as.PutOp(as.NewBin(bin, writes[k][bin])))
+		}
+		batch = append(batch, as.NewBatchWrite(wp, key, ops...))
+	}
+	for _, k := range deletes {
+		key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), k)
+		if err != nil {
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Invalid record key", err.Error())
+			return diags
+		}
+		batch = append(batch, as.NewBatchDelete(nil, key))
+	}
+	if len(batch) == 0 {
+		return diags
+	}
+
+	if err := r.asConn.getClient(ctx).BatchOperate(nil, batch); err != nil {
+		diags.AddError("Error writing records", err.Error())
+		return diags
+	}
+
+	for _, b := range batch {
+		rec := b.BatchRec()
+		k := rec.Key.Value().String()
+		_, isDelete := b.(*as.BatchDelete)
+		switch {
+		case rec.ResultCode == astypes.OK:
+		case rec.ResultCode == astypes.KEY_NOT_FOUND_ERROR && isDelete:
+			// deleting a record that no longer exists
+		case rec.ResultCode == astypes.KEY_EXISTS_ERROR:
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Record already exists",
+				"Record "+k+" already exists in "+recordsSetID(data)+". Remove it or manage it with aerospike_record")
+		default:
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Error writing record", batchRecordError(rec))
+		}
+	}
+
+	return diags
+}
+
+// read reads the records with a batch read, returning the existing records with the bins data manages and their
+// generations.
+func (r *AerospikeRecords) read(ctx context.Context, data AerospikeRecordsModel, keys []string) (map[string]map[string]types.String, map[string]int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	records := make(map[string]map[string]types.String, len(keys))
+	generations := make(map[string]int64, len(keys))
+
+	batch := make([]as.BatchRecordIfc, 0, len(keys))
+	for _, k := range keys {
+		key, err := as.NewKey(data.Namespace.ValueString(), data.Set.ValueString(), k)
+		if err != nil {
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Invalid record key", err.Error())
+			return records, generations, diags
+		}
+		batch = append(batch, as.NewBatchRead(nil, key, nil))
+	}
+	if len(batch) == 0 {
+		return records, generations, diags
+	}
+
+	if err := r.asConn.getClient(ctx).BatchOperate(nil, batch); err != nil {
+		diags.AddError("Error reading records", err.Error())
+		return records, generations, diags
+	}
+
+	for _, b := range batch {
+		rec := b.BatchRec()
+		k := rec.Key.Value().String()
+		switch rec.ResultCode {
+		case astypes.OK:
+			// only the bins in data are refreshed, so other bins don't show up as drift
+			bins, err := managedBins(rec.Record.Bins, data.Records[k])
+			if err != nil {
+				diags.AddAttributeError(path.Root("records").AtMapKey(k), "Unsupported bin type",
+					"Record "+k+" in "+recordsSetID(data)+": "+err.Error())
+				continue
+			}
+			records[k] = bins
+			generations[k] = int64(rec.Record.Generation)
+		case astypes.KEY_NOT_FOUND_ERROR:
+			tflog.Trace(ctx, "record "+k+" in "+recordsSetID(data)+" does not exist")
+		default:
+			diags.AddAttributeError(path.Root("records").AtMapKey(k), "Error reading record", batchRecordError(rec))
+		}
+	}
+
+	return records, generations, diags
+}
+
+// readGenerations sets the generations of the records just written.
+func (r *AerospikeRecords) readGenerations(ctx context.Context, data *AerospikeRecordsModel) diag.Diagnostics {
+	_, generations, diags := r.read(ctx, *data, sortedKeys(data.Records))
+	data.Generations = generationsMap(generations)
+
+	return diags
+}
+
+// recordsChanges returns the bins to write for the records of plan that are new or differ from state, with nil
+// values for bins removed from a record, and the keys of the records to delete.
+func recordsChanges(plan, state map[string]map[string]types.String) (map[string]as.BinMap, []string) {
+	writes := make(map[string]as.BinMap)
+	for k, bins := range plan {
+		stateBins, ok := state[k]
+		changed := !ok || len(bins) != len(stateBins)
+		binMap := make(as.BinMap, len(bins))
+		for bin, v := range bins {
+			binMap[bin] = v.ValueString()
+			if sv, ok := stateBins[bin]; !ok || !sv.Equal(v) {
+				changed = true
+			}
+		}
+		for bin := range stateBins {
+			if _, ok := bins[bin]; !ok {
+				binMap[bin] = nil
+			}
+		}
+		if changed {
+			writes[k] = binMap
+		}
+	}
+
+	deletes := make([]string, 0)
+	for _, k := range sortedKeys(state) {
+		if _, ok := plan[k]; !ok {
+			deletes = append(deletes, k)
+		}
+	}
+
+	return writes, deletes
+}
+
+// generationsMap converts record generations to a terraform map.
+func generationsMap(generations map[string]int64) types.Map {
+	elements := make(map[string]attr.Value, len(generations))
+	for k, g := range generations {
+		elements[k] = types.Int64Value(g)
+	}
+
+	return types.MapValueMust(types.Int64Type, elements)
+}
+
+// batchRecordError returns the error of a batch record that failed.
+func batchRecordError(rec *as.BatchRecord) string {
+	if rec.Err != nil {
+		return rec.Err.Error()
+	}
+
+	return rec.ResultCode.String()
+}
+
+func recordsSetID(data AerospikeRecordsModel) string {
+	return data.Namespace.ValueString() + ":" + data.Set.ValueString()
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeRecords(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeRecordsConfig(`
+    r1 = { bin1 = "a", bin2 = "b" }
+    r2 = { bin1 = "c" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.%", "2"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.r1.bin2", "b"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "generations.%", "2"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "generations.r1", "1"),
+				),
+			},
+			// update a record, remove a bin, drop a record and add one
+			{
+				Config: testAccAerospikeRecordsConfig(`
+    r1 = { bin1 = "d" }
+    r3 = { bin1 = "e" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.%", "2"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.r1.%", "1"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.r1.bin1", "d"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "records.r3.bin1", "e"),
+					resource.TestCheckResourceAttr("aerospike_records.test", "generations.r1", "2"),
+					resource.TestCheckNoResourceAttr("aerospike_records.test", "generations.r2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAerospikeRecordsConfig(records string) string {
+	return `
+resource "aerospike_records" "test" {
+  namespace = "aerospike"
+  set       = "testrecords"
+  records = {` + records + `
+  }
+}`
+}
+
+func TestRecordsChanges(t *testing.T) {
+	state := map[string]map[string]types.String{
+		"same":    {"a": types.StringValue("1")},
+		"changed": {"a": types.StringValue("1"), "b": types.StringValue("2")},
+		"removed": {"a": types.StringValue("1")},
+	}
+	plan := map[string]map[string]types.String{
+		"same":    {"a": types.StringValue("1")},
+		"changed": {"a": types.StringValue("3")},
+		"new":     {"a": types.StringValue("4")},
+	}
+
+	writes, deletes := recordsChanges(plan, state)
+	wantWrites := map[string]as.BinMap{
+		"changed": {"a": "3", "b": nil},
+		"new":     {"a": "4"},
+	}
+	if !reflect.DeepEqual(writes, wantWrites) {
+		t.Errorf("recordsChanges() writes = %v, want %v", writes, wantWrites)
+	}
+	if want := []string{"removed"}; !reflect.DeepEqual(deletes, want) {
+		t.Errorf("recordsChanges() deletes = %v, want %v", deletes, want)
+	}
+}
+
+func TestAerospikeRecordsRead(t *testing.T) {
+	client := newFakeClient()
+	conn := newFakeConnection(client, "admin")
+	key, _ := as.NewKey("test", "seed", "r1")
+	_ = client.Put(nil, key, as.BinMap{"a": 1, "b": "x"})
+
+	r := &AerospikeRecords{asConn: conn}
+	data := AerospikeRecordsModel{Namespace: types.StringValue("test"), Set: types.StringValue("seed"),
+		Records: map[string]map[string]types.String{"r1": {"b": types.StringValue("old")}, "r2": {"b": types.StringValue("y")}}}
+
+	// records deleted outside terraform and bins it doesn't manage are left out
+	records, generations, diags := r.read(context.Background(), data, []string{"r1", "r2"})
+	if diags.HasError() {
+		t.Fatalf("read() diagnostics = %v", diags)
+	}
+	wantRecords := map[string]map[string]types.String{"r1": {"b": types.StringValue("x")}}
+	if !reflect.DeepEqual(records, wantRecords) {
+		t.Errorf("read() records = %v, want %v", records, wantRecords)
+	}
+	if want := map[string]int64{"r1": 1}; !reflect.DeepEqual(generations, want) {
+		t.Errorf("read() generations = %v, want %v", generations, want)
+	}
+
+	// an int bin isn't converted to a string that the next write would store
+	data.Records["r1"]["a"] = types.StringValue("1")
+	if _, _, diags := r.read(context.Background(), data, []string{"r1"}); !diags.HasError() {
+		t.Errorf("read() of an int bin succeeded, want an error")
+	}
+
+	// deleting a record that is already gone isn't an error
+	diags = r.write(context.Background(), data, nil, []string{"r1", "r2"}, nil)
+	if diags.HasError() {
+		t.Fatalf("write() diagnostics = %v", diags)
+	}
+	if len(client.records) != 0 {
+		t.Errorf("records left after delete: %v", client.records)
+	}
+}
@@ -9,12 +9,15 @@ import (
 	as "github.com/aerospike/aerospike-client-go/v7"
 	astypes "github.com/aerospike/aerospike-client-go/v7/types"
 	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
@@ -22,6 +25,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"math"
+	"net"
 	"reflect"
 	"strings"
 )
@@ -29,11 +34,41 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AerospikeRole{}
 var _ resource.ResourceWithImportState = &AerospikeRole{}
+var _ resource.ResourceWithModifyPlan = &AerospikeRole{}
+var _ resource.ResourceWithUpgradeState = &AerospikeRole{}
 
 func NewAerospikeRole() resource.Resource {
 	return &AerospikeRole{}
 }
 
+// privilegeNames are the privileges a role can be granted. Each one also has a predefined role with the same name.
+var privilegeNames = []string{"user-admin", "sys-admin", "data-admin", "udf-admin",
+	"sindex-admin", "read-write-udf", "read-write", "read", "write", "truncate"}
+
+// privilegeMinVersions are the server versions that introduced privileges. Privileges that aren't listed are
+// supported by every server version the provider works with.
+var privilegeMinVersions = map[string]string{
+	"sindex-admin": "6.0",
+	"udf-admin":    "6.0",
+	"truncate":     "6.0",
+}
+
+// unsupportedPrivilege returns an error message if the server version doesn't support the privilege, or "" if it does.
+func unsupportedPrivilege(privilege, version string) string {
+	minVersion, ok := privilegeMinVersions[privilege]
+	if !ok || versionAtLeast(version, minVersion) {
+		return ""
+	}
+
+	return fmt.Sprintf("Privilege %s requires server version %s or later, the cluster runs %s", privilege, minVersion, version)
+}
+
+// maxWhiteListEntries is the largest white list the server accepts for a role.
+const maxWhiteListEntries = 32
+
+// maxQuota is the largest quota the server accepts. Quotas are sent to the server as unsigned 32 bit integers.
+const maxQuota int64 = math.MaxUint32
+
 // AerospikeRole defines the resource implementation.
 type AerospikeRole struct {
 	asConn *asConnection
@@ -41,11 +76,19 @@ type AerospikeRole struct {
 
 // AerospikeRoleModel describes the resource data model.
 type AerospikeRoleModel struct {
-	Role_name   types.String   `tfsdk:"role_name"`
-	Privileges  types.Set      `tfsdk:"privileges"`
-	White_list  []types.String `tfsdk:"white_list"`
-	Read_quota  types.Int64    `tfsdk:"read_quota"`
-	Write_quota types.Int64    `tfsdk:"write_quota"`
+	Role_name   types.String `tfsdk:"role_name"`
+	Privileges  types.Set    `tfsdk:"privileges"`
+	White_list  types.Set    `tfsdk:"white_list"`
+	Read_quota  types.Int64  `tfsdk:"read_quota"`
+	Write_quota types.Int64  `tfsdk:"write_quota"`
+
+	Resolve_white_list  types.Bool `tfsdk:"resolve_white_list"`
+	Resolved_white_list types.Set  `tfsdk:"resolved_white_list"`
+
+	Deletion_protection types.Bool `tfsdk:"deletion_protection"`
+	Adopt_existing      types.Bool `tfsdk:"adopt_existing"`
+
+	Connection types.Object `tfsdk:"connection"`
 }
 
 type AerospikeRolePrivilegeModel struct {
@@ -62,11 +105,14 @@ func (r *AerospikeRole) Schema(ctx context.Context, req resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		Description: "Aerospike Role",
+		// Version 1 changed white_list from a list to a set
+		Version: 1,
 
 		Attributes: map[string]schema.Attribute{
 			"role_name": schema.StringAttribute{
 				Description: "Role name",
 				Required:    true,
+				Validators:  securityNameValidators(stringvalidator.NoneOf(privilegeNames...)),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -77,11 +123,10 @@ func (r *AerospikeRole) Schema(ctx context.Context, req resource.SchemaRequest,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"privilege": schema.StringAttribute{
-							Description: "Privilege name",
+							Description: "Privilege name. Case and surrounding whitespace are ignored",
 							Required:    true,
 							Validators: []validator.String{
-								stringvalidator.OneOf("user-admin", "sys-admin", "data-admin", "udf-admin",
-									"sindex-admin", "read-write-udf", "read-write", "read", "write", "truncate"),
+								privilegeNameValidator{},
 							},
 						},
 						"namespace": schema.StringAttribute{
@@ -105,22 +150,139 @@ func (r *AerospikeRole) Schema(ctx context.Context, req resource.SchemaRequest,
 					},
 				},
 			},
-			"white_list": schema.ListAttribute{
-				Description: "A list of IP addresses allowed to connect.",
+			"white_list": schema.SetAttribute{
+				Description: fmt.Sprintf("A set of IP addresses allowed to connect, or host names with resolve_white_list. At most %d entries", maxWhiteListEntries),
 				Optional:    true,
 				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtMost(maxWhiteListEntries),
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"resolve_white_list": schema.BoolAttribute{
+				Description: "Resolve the host names in white_list to their IP addresses. The names are resolved again on every plan, " +
+					"and the role is updated when their addresses change. Defaults to false",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"resolved_white_list": schema.SetAttribute{
+				Description: "Addresses set as the role's white list on the server, white_list with its host names resolved when resolve_white_list is set",
+				Computed:    true,
+				ElementType: types.StringType,
 			},
 			"read_quota": schema.Int64Attribute{
-				Description: "Read quota to apply to the role",
+				Description: fmt.Sprintf("Read quota to apply to the role, in records per second. Between 0 (no quota) and %d", maxQuota),
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.Between(0, maxQuota),
+				},
 			},
 			"write_quota": schema.Int64Attribute{
-				Description: "write quota to apply to the role",
+				Description: fmt.Sprintf("write quota to apply to the role, in records per second. Between 0 (no quota) and %d", maxQuota),
 				Optional:    true,
 				Computed:    true,
 				Default:     int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.Between(0, maxQuota),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevent the role from being dropped while set to true",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "If the role already exists when it's created, take it over and set its privileges, white list and quotas instead of failing",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"connection": connectionAttribute(),
+		},
+	}
+}
+
+func (r *AerospikeRole) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// white_list was a list in version 0. The model reads both lists and sets, so the state is copied as is
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"role_name": schema.StringAttribute{
+						Required: true,
+					},
+					"privileges": schema.SetNestedAttribute{
+						Required: true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"privilege": schema.StringAttribute{
+									Required: true,
+								},
+								"namespace": schema.StringAttribute{
+									Optional: true,
+								},
+								"set": schema.StringAttribute{
+									Optional: true,
+								},
+							},
+						},
+					},
+					"white_list": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"read_quota": schema.Int64Attribute{
+						Optional: true,
+						Computed: true,
+					},
+					"write_quota": schema.Int64Attribute{
+						Optional: true,
+						Computed: true,
+					},
+					"deletion_protection": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"adopt_existing": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					// connection and the white list resolution attributes are listed so the state decodes into the current
+					// model, version 0 states have them as null
+					"connection": connectionAttribute(),
+					"resolve_white_list": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"resolved_white_list": schema.SetAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var data AerospikeRoleModel
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				if data.Deletion_protection.IsNull() {
+					data.Deletion_protection = types.BoolValue(false)
+				}
+				if data.Adopt_existing.IsNull() {
+					data.Adopt_existing = types.BoolValue(false)
+				}
+				if data.Resolve_white_list.IsNull() {
+					data.Resolve_white_list = types.BoolValue(false)
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 			},
 		},
 	}
@@ -143,12 +305,179 @@ func (r *AerospikeRole) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
 	r.asConn = asConn
 }
 
+func (r *AerospikeRole) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check before the provider is configured
+	if r.asConn == nil {
+		return
+	}
+
+	// dropping or revoking privileges from a role of the provider user fails at plan time, not halfway through an apply
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(r.checkPlannedRevoke(ctx, req)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Nothing else to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan AerospikeRoleModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	// white_list may come from other resources, it is resolved and checked once it is known
+	if resp.Diagnostics.HasError() || plan.White_list.IsUnknown() || plan.Resolve_white_list.IsUnknown() {
+		return
+	}
+
+	// host names are resolved at plan time, so changed addresses show up as a diff and the apply sets what was planned
+	whiteList, err := resolveRoleWhiteList(ctx, plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("white_list"), "Unable to resolve white list", err.Error())
+		return
+	}
+	if len(whiteList) > maxWhiteListEntries {
+		resp.Diagnostics.AddAttributeError(path.Root("white_list"), "White list too long",
+			fmt.Sprintf("white_list resolves to %d addresses, the server accepts at most %d", len(whiteList), maxWhiteListEntries))
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_white_list"), stringSet(whiteList))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, plan.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The proxy client can't read the server configuration, leave the checks to the server
+	if !r.asConn.supportsInfo() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.checkPrivilegeVersions(ctx, plan)...)
+
+	if plan.Read_quota.ValueInt64() == 0 && plan.Write_quota.ValueInt64() == 0 {
+		return
+	}
+
+	enabled, err := r.asConn.quotasEnabled(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to check if quotas are enabled", err.Error())
+		return
+	}
+
+	if !enabled {
+		if plan.Read_quota.ValueInt64() != 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("read_quota"), "Quotas not enabled",
+				"read_quota is set but quotas are not enabled in the server. Set enable-quotas in the security context first")
+		}
+		if plan.Write_quota.ValueInt64() != 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("write_quota"), "Quotas not enabled",
+				"write_quota is set but quotas are not enabled in the server. Set enable-quotas in the security context first")
+		}
+	}
+}
+
+// checkPlannedRevoke reports a planned destroy or replacement of a role, or revocation of its privileges, when the
+// provider user holds the role.
+func (r *AerospikeRole) checkPlannedRevoke(ctx context.Context, req resource.ModifyPlanRequest) diag.Diagnostics {
+	var state, plan AerospikeRoleModel
+
+	diags := req.State.Get(ctx, &state)
+	if diags.HasError() {
+		return diags
+	}
+	roleName := state.Role_name.ValueString()
+
+	operation := "Dropping role " + roleName
+	if !req.Plan.Raw.IsNull() {
+		diags.Append(req.Plan.Get(ctx, &plan)...)
+		if diags.HasError() || plan.Privileges.IsUnknown() || plan.Connection.IsUnknown() {
+			return diags
+		}
+
+		// changing role_name or connection re-creates the role
+		if plan.Role_name.Equal(state.Role_name) && plan.Connection.Equal(state.Connection) {
+			revoked := revokedPrivileges(privilegesFromSet(ctx, state.Privileges), privilegesFromSet(ctx, plan.Privileges))
+			if len(revoked) == 0 {
+				return diags
+			}
+			operation = "Revoking privileges " + privsToStr(revoked) + " from role " + roleName
+		}
+	}
+
+	r, connDiags := r.withConnection(ctx, state.Connection)
+	diags.Append(connDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	return append(diags, r.asConn.checkProviderRole(ctx, roleName, operation)...)
+}
+
+// checkPrivilegeVersions reports privileges in the plan that the cluster's server version doesn't support.
+func (r *AerospikeRole) checkPrivilegeVersions(ctx context.Context, plan AerospikeRoleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.Privileges.IsUnknown() || plan.Privileges.IsNull() {
+		return diags
+	}
+
+	privElements := make([]types.Object, 0, len(plan.Privileges.Elements()))
+	diags.Append(plan.Privileges.ElementsAs(ctx, &privElements, false)...)
+
+	needsCheck := false
+	for _, p := range privElements {
+		var privModel AerospikeRolePrivilegeModel
+		p.As(ctx, &privModel, basetypes.ObjectAsOptions{})
+		if _, ok := privilegeMinVersions[normalizePrivilegeName(privModel.Privilege.ValueString())]; ok {
+			needsCheck = true
+		}
+	}
+	if !needsCheck {
+		return diags
+	}
+
+	version, err := r.asConn.serverVersion(ctx)
+	if err != nil {
+		diags.AddWarning("Unable to check the server version", err.Error())
+		return diags
+	}
+
+	for _, p := range privElements {
+		var privModel AerospikeRolePrivilegeModel
+		p.As(ctx, &privModel, basetypes.ObjectAsOptions{})
+		if msg := unsupportedPrivilege(normalizePrivilegeName(privModel.Privilege.ValueString()), version); msg != "" {
+			diags.AddAttributeError(path.Root("privileges"), "Privilege not supported", msg)
+		}
+	}
+
+	return diags
+}
+
 func (r *AerospikeRole) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_role")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data AerospikeRoleModel
-	adminPol := as.NewAdminPolicy()
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -157,6 +486,15 @@ func (r *AerospikeRole) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	adminPol := r.asConn.adminPolicy
+
 	roleName := data.Role_name.ValueString()
 	readQuota := uint32(data.Read_quota.ValueInt64())
 	writeQuota := uint32(data.Write_quota.ValueInt64())
@@ -169,7 +507,7 @@ func (r *AerospikeRole) Create(ctx context.Context, req resource.CreateRequest,
 		var privModel AerospikeRolePrivilegeModel
 		p.As(ctx, &privModel, basetypes.ObjectAsOptions{})
 
-		if !privModel.Namespace.IsNull() && !r.namespaceExists(privModel.Namespace.ValueString()) {
+		if !privModel.Namespace.IsNull() && !r.namespaceExists(ctx, privModel.Namespace.ValueString()) {
 			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Invalid namesace", "Namespace \""+privModel.Namespace.ValueString()+"\" does not exist in the cluster. Can't create role referencing it"))
 			return
 		}
@@ -179,12 +517,15 @@ func (r *AerospikeRole) Create(ctx context.Context, req resource.CreateRequest,
 		printPrivs = append(printPrivs, privToStr(tmpPriv))
 	}
 
-	whiteList := make([]string, 0)
-	for _, w := range data.White_list {
-		whiteList = append(whiteList, w.ValueString())
+	privileges = dedupePrivileges(privileges)
+
+	whiteList, resolveErr := plannedWhiteList(ctx, &data)
+	if resolveErr != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("white_list"), "Unable to resolve white list", resolveErr.Error())
+		return
 	}
 
-	err := (*r.asConn.client).CreateRole(adminPol, roleName, privileges, whiteList,
+	err := r.asConn.getClient(ctx).CreateRole(adminPol, roleName, privileges, whiteList,
 		readQuota, writeQuota)
 	if err != nil {
 		switch {
@@ -192,12 +533,18 @@ func (r *AerospikeRole) Create(ctx context.Context, req resource.CreateRequest,
 			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Quotas not enabled",
 				"Role quotas are requests but not enabled in the server"))
 			return
+		case err.Matches(astypes.ROLE_ALREADY_EXISTS) && data.Adopt_existing.ValueBool():
+			resp.Diagnostics.Append(r.adoptRole(ctx, roleName, privileges, whiteList, readQuota, writeQuota)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
 		case err.Matches(astypes.ROLE_ALREADY_EXISTS):
 			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Role already exists",
-				"Role that was being created already exists: "+roleName))
+				"Role that was being created already exists: "+roleName+". Import it or set adopt_existing"))
 			return
 		default:
-			panic(err)
+			resp.Diagnostics.AddError("Error creating role", err.Error())
+			return
 		}
 	}
 
@@ -220,17 +567,26 @@ func (r *AerospikeRole) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	adminPol := r.asConn.adminPolicy
 
-	role, err := (*r.asConn.client).QueryRole(adminPol, data.Role_name.ValueString())
+	role, err := r.asConn.getClient(ctx).QueryRole(adminPol, data.Role_name.ValueString())
 	if err != nil && !err.Matches(astypes.INVALID_ROLE) {
-		panic(err)
+		resp.Diagnostics.AddError("Error reading role", err.Error())
+		return
 	}
 
 	if err != nil && err.Matches(astypes.INVALID_ROLE) {
 		data.Role_name = types.StringNull()
 		data.Privileges = types.SetNull(privObjectType())
-		data.White_list = nil
+		data.White_list = types.SetNull(types.StringType)
+		data.Resolved_white_list = types.SetNull(types.StringType)
 		data.Read_quota = types.Int64Null()
 		data.Write_quota = types.Int64Null()
 
@@ -243,6 +599,9 @@ func (r *AerospikeRole) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	if len(role.Privileges) == 0 {
 		data.Privileges = types.SetNull(privObjectType())
+	} else if samePrivileges(privilegesFromSet(ctx, data.Privileges), role.Privileges) {
+		// keep the privileges as they are written in the configuration, the server only differs in case or whitespace
+		tflog.Trace(ctx, "privileges of role "+data.Role_name.ValueString()+" match the state")
 	} else {
 		privsAttrSlice := make([]attr.Value, 0)
 
@@ -261,18 +620,30 @@ func (r *AerospikeRole) Read(ctx context.Context, req resource.ReadRequest, resp
 		}
 	}
 
-	if len(role.Whitelist) == 0 {
-		data.White_list = nil
-	} else {
-		data.White_list = make([]types.String, 0)
-		for _, w := range role.Whitelist {
-			data.White_list = append(data.White_list, types.StringValue(w))
+	if data.Resolve_white_list.IsNull() {
+		data.Resolve_white_list = types.BoolValue(false)
+	}
+
+	// with resolve_white_list the server has the addresses and white_list keeps the configured host names
+	if !data.Resolve_white_list.ValueBool() {
+		if len(role.Whitelist) == 0 {
+			data.White_list = types.SetNull(types.StringType)
+		} else {
+			data.White_list = stringSet(role.Whitelist)
 		}
 	}
+	data.Resolved_white_list = stringSet(role.Whitelist)
 
 	data.Read_quota = types.Int64Value(int64(role.ReadQuota))
 	data.Write_quota = types.Int64Value(int64(role.WriteQuota))
 
+	if data.Deletion_protection.IsNull() {
+		data.Deletion_protection = types.BoolValue(false)
+	}
+	if data.Adopt_existing.IsNull() {
+		data.Adopt_existing = types.BoolValue(false)
+	}
+
 	tflog.Trace(ctx, "read role "+role.Name)
 
 	// Save updated data into Terraform state
@@ -281,6 +652,11 @@ func (r *AerospikeRole) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_role")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan, state, data AerospikeRoleModel
 
 	// Read Terraform plan data into the model
@@ -291,9 +667,19 @@ func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, plan.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	adminPol := r.asConn.adminPolicy
 
 	data.Role_name = plan.Role_name
+	data.Deletion_protection = plan.Deletion_protection
+	data.Adopt_existing = plan.Adopt_existing
+	data.Resolve_white_list = plan.Resolve_white_list
 
 	//privileges
 	if reflect.DeepEqual(plan.Privileges, state.Privileges) {
@@ -310,7 +696,7 @@ func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest,
 			var privModel AerospikeRolePrivilegeModel
 			p.As(ctx, &privModel, basetypes.ObjectAsOptions{})
 
-			if !privModel.Namespace.IsNull() && !r.namespaceExists(privModel.Namespace.ValueString()) {
+			if !privModel.Namespace.IsNull() && !r.namespaceExists(ctx, privModel.Namespace.ValueString()) {
 				resp.Diagnostics.Append(diag.NewErrorDiagnostic("Invalid namesace", "Namespace \""+privModel.Namespace.ValueString()+"\" does not exist in the cluster. Can't create role referencing it"))
 				return
 			}
@@ -329,31 +715,9 @@ func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest,
 			stateASPrivileges = append(stateASPrivileges, tmpPriv)
 		}
 
-		privsToAdd := make([]as.Privilege, 0)
-		for _, p := range planASPrivileges {
-			if !sliceutil.Contains(stateASPrivileges, p) {
-				privsToAdd = append(privsToAdd, p)
-			}
-		}
-
-		privsToRevoke := make([]as.Privilege, 0)
-		for _, p := range stateASPrivileges {
-			if !sliceutil.Contains(planASPrivileges, p) {
-				privsToRevoke = append(privsToRevoke, p)
-			}
-		}
-
-		if len(privsToAdd) > 0 {
-			err := (*r.asConn.client).GrantPrivileges(adminPol, plan.Role_name.ValueString(), privsToAdd)
-			if err != nil {
-				panic(err)
-			}
-		}
-		if len(privsToRevoke) > 0 {
-			err := (*r.asConn.client).RevokePrivileges(adminPol, plan.Role_name.ValueString(), privsToRevoke)
-			if err != nil {
-				panic(err)
-			}
+		resp.Diagnostics.Append(r.syncPrivileges(ctx, plan.Role_name.ValueString(), stateASPrivileges, planASPrivileges)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
 
 		data.Privileges = plan.Privileges
@@ -361,27 +725,36 @@ func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	//whitelist
-	if !reflect.DeepEqual(plan.White_list, state.White_list) {
-		whiteList := make([]string, 0)
-		for _, w := range plan.White_list {
-			whiteList = append(whiteList, w.ValueString())
-		}
-		err := (*r.asConn.client).SetWhitelist(adminPol, data.Role_name.ValueString(), whiteList)
+	whiteList, err := plannedWhiteList(ctx, &plan)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("white_list"), "Unable to resolve white list", err.Error())
+		return
+	}
+	// states written before resolved_white_list was added only have white_list
+	stateWhiteList := setStrings(state.White_list)
+	if !state.Resolved_white_list.IsNull() {
+		stateWhiteList = setStrings(state.Resolved_white_list)
+	}
+	if !sameStrings(whiteList, stateWhiteList) {
+		err := r.asConn.getClient(ctx).SetWhitelist(adminPol, data.Role_name.ValueString(), whiteList)
 		if err != nil {
-			panic(err)
+			resp.Diagnostics.AddError("Error setting white list", err.Error())
+			return
 		}
 	}
 	data.White_list = plan.White_list
+	data.Resolved_white_list = plan.Resolved_white_list
 
 	//qoutas
 	if plan.Read_quota != state.Read_quota || plan.Write_quota != state.Write_quota {
-		err := (*r.asConn.client).SetQuotas(adminPol, data.Role_name.ValueString(), uint32(plan.Read_quota.ValueInt64()),
+		err := r.asConn.getClient(ctx).SetQuotas(adminPol, data.Role_name.ValueString(), uint32(plan.Read_quota.ValueInt64()),
 			uint32(plan.Write_quota.ValueInt64()))
 		if err != nil && err.Matches(astypes.QUOTAS_NOT_ENABLED) {
 			resp.Diagnostics.Append(diag.NewErrorDiagnostic("Quotas not enabled", "Role quotas are requests but not enabled in the server"))
 			return
 		} else if err != nil {
-			panic(err)
+			resp.Diagnostics.AddError("Error setting quotas", err.Error())
+			return
 		}
 	}
 	data.Read_quota = plan.Read_quota
@@ -392,6 +765,11 @@ func (r *AerospikeRole) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *AerospikeRole) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_role")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data AerospikeRoleModel
 
 	// Read Terraform prior state data into the model
@@ -401,11 +779,35 @@ func (r *AerospikeRole) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Deletion_protection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_protection"), "Deletion protection enabled",
+			"Role "+data.Role_name.ValueString()+" has deletion_protection set. Set it to false and apply before destroying the role")
+		return
+	}
+
+	resp.Diagnostics.Append(r.asConn.checkDestructive("Dropping role " + data.Role_name.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.asConn.checkProviderRole(ctx, data.Role_name.ValueString(), "Dropping role "+data.Role_name.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	err := (*r.asConn.client).DropRole(adminPol, data.Role_name.ValueString())
+	adminPol := r.asConn.adminPolicy
+
+	err := r.asConn.getClient(ctx).DropRole(adminPol, data.Role_name.ValueString())
 	if err != nil && !err.Matches(astypes.INVALID_ROLE) {
-		panic(err)
+		resp.Diagnostics.AddError("Error dropping role", err.Error())
+		return
 	}
 
 	// Write logs using the tflog package
@@ -417,25 +819,207 @@ func (r *AerospikeRole) ImportState(ctx context.Context, req resource.ImportStat
 	resource.ImportStatePassthroughID(ctx, path.Root("role_name"), req, resp)
 }
 
+// resolveRoleWhiteList returns the addresses to set as the role's white list, with the host names resolved when
+// resolve_white_list is set.
+func resolveRoleWhiteList(ctx context.Context, data AerospikeRoleModel) ([]string, error) {
+	whiteList := setStrings(data.White_list)
+	if !data.Resolve_white_list.ValueBool() {
+		return whiteList, nil
+	}
+
+	return resolveWhiteList(ctx, whiteList, net.DefaultResolver.LookupHost)
+}
+
+// plannedWhiteList returns the planned resolved_white_list, resolving white_list if the plan doesn't have it.
+func plannedWhiteList(ctx context.Context, data *AerospikeRoleModel) ([]string, error) {
+	if !data.Resolved_white_list.IsNull() && !data.Resolved_white_list.IsUnknown() {
+		return setStrings(data.Resolved_white_list), nil
+	}
+
+	whiteList, err := resolveRoleWhiteList(ctx, *data)
+	if err != nil {
+		return nil, err
+	}
+	data.Resolved_white_list = stringSet(whiteList)
+
+	return whiteList, nil
+}
+
+// adoptRole takes over an existing role, setting its privileges, white list and quotas to the planned values.
+func (r *AerospikeRole) adoptRole(ctx context.Context, roleName string, privileges []as.Privilege, whiteList []string, readQuota, writeQuota uint32) diag.Diagnostics {
+	adminPol := r.asConn.adminPolicy
+
+	tflog.Trace(ctx, "role "+roleName+" already exists, adopting it")
+
+	role, err := r.asConn.getClient(ctx).QueryRole(adminPol, roleName)
+	if err != nil {
+		return diag.Diagnostics{diag.NewErrorDiagnostic("Error adopting role", err.Error())}
+	}
+
+	diags := r.syncPrivileges(ctx, roleName, role.Privileges, privileges)
+	if diags.HasError() {
+		return diags
+	}
+
+	if !sameStrings(role.Whitelist, whiteList) {
+		err = r.asConn.getClient(ctx).SetWhitelist(adminPol, roleName, whiteList)
+		if err != nil {
+			return diag.Diagnostics{diag.NewErrorDiagnostic("Error adopting role", err.Error())}
+		}
+	}
+
+	if role.ReadQuota != readQuota || role.WriteQuota != writeQuota {
+		err = r.asConn.getClient(ctx).SetQuotas(adminPol, roleName, readQuota, writeQuota)
+		if err != nil && err.Matches(astypes.QUOTAS_NOT_ENABLED) {
+			return diag.Diagnostics{diag.NewErrorDiagnostic("Quotas not enabled", "Role quotas are requests but not enabled in the server")}
+		} else if err != nil {
+			return diag.Diagnostics{diag.NewErrorDiagnostic("Error adopting role", err.Error())}
+		}
+	}
+
+	return nil
+}
+
+// syncPrivileges grants and revokes privileges so the role ends up with exactly planPrivileges.
+func (r *AerospikeRole) syncPrivileges(ctx context.Context, roleName string, currentPrivileges, planPrivileges []as.Privilege) diag.Diagnostics {
+	adminPol := r.asConn.adminPolicy
+
+	// entries that only differ in case or whitespace are the same privilege
+	currentPrivileges = dedupePrivileges(currentPrivileges)
+	planPrivileges = dedupePrivileges(planPrivileges)
+
+	privsToAdd := make([]as.Privilege, 0)
+	for _, p := range planPrivileges {
+		if !sliceutil.Contains(currentPrivileges, p) {
+			privsToAdd = append(privsToAdd, p)
+		}
+	}
+
+	privsToRevoke := revokedPrivileges(currentPrivileges, planPrivileges)
+	if len(privsToRevoke) > 0 {
+		diags := r.asConn.checkProviderRole(ctx, roleName, "Revoking privileges "+privsToStr(privsToRevoke)+" from role "+roleName)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if len(privsToAdd) > 0 {
+		err := r.asConn.getClient(ctx).GrantPrivileges(adminPol, roleName, privsToAdd)
+		if err != nil {
+			return diag.Diagnostics{diag.NewErrorDiagnostic("Error granting privileges", err.Error())}
+		}
+	}
+	if len(privsToRevoke) > 0 {
+		err := r.asConn.getClient(ctx).RevokePrivileges(adminPol, roleName, privsToRevoke)
+		if err != nil {
+			return diag.Diagnostics{diag.NewErrorDiagnostic("Error revoking privileges", err.Error())}
+		}
+	}
+
+	return nil
+}
+
+// revokedPrivileges returns the privileges in currentPrivileges that aren't in planPrivileges.
+func revokedPrivileges(currentPrivileges, planPrivileges []as.Privilege) []as.Privilege {
+	revoked := make([]as.Privilege, 0)
+	for _, p := range dedupePrivileges(currentPrivileges) {
+		if !sliceutil.Contains(planPrivileges, p) {
+			revoked = append(revoked, p)
+		}
+	}
+
+	return revoked
+}
+
 func privToStr(privilege as.Privilege) string {
 	return "(" + string(privilege.Code) + "," + privilege.Namespace + "," + privilege.SetName + ")"
 }
 
-func (r *AerospikeRole) namespaceExists(namespace string) bool {
+func (r *AerospikeRole) namespaceExists(ctx context.Context, namespace string) bool {
 	key, _ := as.NewKey(namespace, "dummy", "dummy")
 
-	_, err := (*r.asConn.client).Get(nil, key)
+	_, err := r.asConn.getClient(ctx).Get(r.asConn.readPolicy, key)
 
 	return !err.Matches(astypes.INVALID_NAMESPACE)
 
 }
 
+// normalizePrivilegeName returns the privilege name as the server reports it.
+func normalizePrivilegeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// privilegeNameValidator accepts the privilege names in any case and with surrounding whitespace. They are
+// normalized before they are sent to the server.
+type privilegeNameValidator struct{}
+
+func (v privilegeNameValidator) Description(ctx context.Context) string {
+	return "value must be one of: " + strings.Join(privilegeNames, ", ")
+}
+
+func (v privilegeNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v privilegeNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !sliceutil.Contains(privilegeNames, normalizePrivilegeName(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid privilege",
+			fmt.Sprintf("%q is not a privilege, %s", req.ConfigValue.ValueString(), v.Description(ctx)))
+	}
+}
+
+// dedupePrivileges removes repeated privileges, keeping the first of each.
+func dedupePrivileges(privileges []as.Privilege) []as.Privilege {
+	result := make([]as.Privilege, 0, len(privileges))
+	for _, p := range privileges {
+		if !sliceutil.Contains(result, p) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// samePrivileges reports whether a and b hold the same privileges, ignoring order and repeats.
+func samePrivileges(a, b []as.Privilege) bool {
+	a, b = dedupePrivileges(a), dedupePrivileges(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for _, p := range a {
+		if !sliceutil.Contains(b, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// privilegesFromSet converts the privileges attribute to client privileges, normalized.
+func privilegesFromSet(ctx context.Context, privileges types.Set) []as.Privilege {
+	privElements := make([]types.Object, 0, len(privileges.Elements()))
+	privileges.ElementsAs(ctx, &privElements, false)
+
+	result := make([]as.Privilege, 0, len(privElements))
+	for _, p := range privElements {
+		var privModel AerospikeRolePrivilegeModel
+		p.As(ctx, &privModel, basetypes.ObjectAsOptions{})
+		result = append(result, asPrivFromStringValues(privModel.Privilege, privModel.Namespace, privModel.Set))
+	}
+
+	return result
+}
+
 func asPrivFromStringValues(priv, namespace, set types.String) as.Privilege {
 	// ugly hack since privilegeCode isn't exported and I couldn't find anything else that worked :(
 	var tmpPriv as.Privilege
-	n := namespace.ValueString()
-	s := set.ValueString()
-	switch priv.ValueString() {
+	n := strings.TrimSpace(namespace.ValueString())
+	s := strings.TrimSpace(set.ValueString())
+	switch normalizePrivilegeName(priv.ValueString()) {
 	case "user-admin":
 		tmpPriv = as.Privilege{Code: as.UserAdmin, Namespace: n, SetName: s}
 	case "sys-admin":
@@ -503,3 +1087,15 @@ func asPrivToStringValues(priv as.Privilege) (types.String, types.String, types.
 func privObjectType() types.ObjectType {
 	return types.ObjectType{AttrTypes: map[string]attr.Type{"privilege": types.StringType, "namespace": types.StringType, "set": types.StringType}}
 }
+
+// withConnection returns the resource bound to the cluster of its connection block, or to the provider's cluster
+// when the block isn't set. Both need security enabled.
+func (r *AerospikeRole) withConnection(ctx context.Context, connection types.Object) (*AerospikeRole, diag.Diagnostics) {
+	conn, diags := r.asConn.overrideConnection(ctx, connection, "aerospike_role")
+	// overrideConnection checks the cluster of the block, the provider's cluster only matters without one
+	if connection.IsNull() {
+		diags.Append(conn.requireSecurity("aerospike_role")...)
+	}
+
+	return &AerospikeRole{asConn: conn}, diags
+}
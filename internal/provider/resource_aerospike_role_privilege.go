@@ -0,0 +1,343 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeRolePrivilege{}
+var _ resource.ResourceWithImportState = &AerospikeRolePrivilege{}
+var _ resource.ResourceWithModifyPlan = &AerospikeRolePrivilege{}
+
+func NewAerospikeRolePrivilege() resource.Resource {
+	return &AerospikeRolePrivilege{}
+}
+
+// AerospikeRolePrivilege defines the resource implementation.
+type AerospikeRolePrivilege struct {
+	asConn *asConnection
+}
+
+// AerospikeRolePrivilegeResourceModel describes the resource data model.
+type AerospikeRolePrivilegeResourceModel struct {
+	Role_name types.String `tfsdk:"role_name"`
+	Privilege types.String `tfsdk:"privilege"`
+	Namespace types.String `tfsdk:"namespace"`
+	Set       types.String `tfsdk:"set"`
+}
+
+func (r *AerospikeRolePrivilege) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_role_privilege"
+}
+
+func (r *AerospikeRolePrivilege) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Grants a single privilege to an existing role. The privilege is revoked when the resource is destroyed. " +
+			"Don't manage the same privilege in an aerospike_role resource",
+
+		Attributes: map[string]schema.Attribute{
+			"role_name": schema.StringAttribute{
+				Description: "Role name",
+				Required:    true,
+				Validators:  securityNameValidators(stringvalidator.NoneOf(privilegeNames...)),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"privilege": schema.StringAttribute{
+				Description: "Privilege name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(privilegeNames...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace. Optional - if null the privilege will apply to all namespaces",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"set": schema.StringAttribute{
+				Description: "Set. Optional - if null the privilege will apply to all sets. Must be used with namespace",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("namespace")),
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeRolePrivilege) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_role_privilege")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeRolePrivilege) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check before the provider is configured
+	if r.asConn == nil {
+		return
+	}
+
+	var plan, state AerospikeRolePrivilegeResourceModel
+
+	// every change re-creates the privilege, revoking it from a role of the provider user fails at plan time
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if !req.Plan.Raw.IsNull() {
+			resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if req.Plan.Raw.IsNull() || plan != state {
+			resp.Diagnostics.Append(r.checkProviderRole(ctx, state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	// Nothing else to check on destroy. The proxy client can't read the server version
+	if req.Plan.Raw.IsNull() || !r.asConn.supportsInfo() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, ok := privilegeMinVersions[plan.Privilege.ValueString()]; !ok {
+		return
+	}
+
+	version, err := r.asConn.serverVersion(ctx)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to check the server version", err.Error())
+		return
+	}
+
+	if msg := unsupportedPrivilege(plan.Privilege.ValueString(), version); msg != "" {
+		resp.Diagnostics.AddAttributeError(path.Root("privilege"), "Privilege not supported", msg)
+	}
+}
+
+func (r *AerospikeRolePrivilege) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_role_privilege")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRolePrivilegeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roleName := data.Role_name.ValueString()
+	priv := asPrivFromStringValues(data.Privilege, data.Namespace, data.Set)
+
+	role, err := r.asConn.getClient(ctx).QueryRole(r.asConn.adminPolicy, roleName)
+	if err != nil {
+		if err.Matches(astypes.INVALID_ROLE) {
+			resp.Diagnostics.AddAttributeError(path.Root("role_name"), "Role does not exist",
+				"Role "+roleName+" does not exist. Create it before granting it privileges")
+			return
+		}
+		resp.Diagnostics.AddError("Error reading role", err.Error())
+		return
+	}
+
+	if sliceutil.Contains(role.Privileges, priv) {
+		resp.Diagnostics.AddError("Privilege already granted",
+			"Role "+roleName+" already has the privilege "+privToStr(priv)+". Import it to manage it with terraform")
+		return
+	}
+
+	err = r.asConn.getClient(ctx).GrantPrivileges(r.asConn.adminPolicy, roleName, []as.Privilege{priv})
+	if err != nil {
+		resp.Diagnostics.AddError("Error granting privilege", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "granted privilege "+privToStr(priv)+" to role "+roleName)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRolePrivilege) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeRolePrivilegeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roleName := data.Role_name.ValueString()
+	priv := asPrivFromStringValues(data.Privilege, data.Namespace, data.Set)
+
+	role, err := r.asConn.getClient(ctx).QueryRole(r.asConn.adminPolicy, roleName)
+	if err != nil {
+		if err.Matches(astypes.INVALID_ROLE) {
+			tflog.Trace(ctx, "read privilege of role "+roleName+" and the role does not exist")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading role", err.Error())
+		return
+	}
+
+	if !sliceutil.Contains(role.Privileges, priv) {
+		tflog.Trace(ctx, "role "+roleName+" no longer has the privilege "+privToStr(priv))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(ctx, "read privilege "+privToStr(priv)+" of role "+roleName)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update is never called with changes since every attribute requires replacement.
+func (r *AerospikeRolePrivilege) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_role_privilege")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRolePrivilegeResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeRolePrivilege) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_role_privilege")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeRolePrivilegeResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.checkProviderRole(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	roleName := data.Role_name.ValueString()
+	priv := asPrivFromStringValues(data.Privilege, data.Namespace, data.Set)
+
+	err := r.asConn.getClient(ctx).RevokePrivileges(r.asConn.adminPolicy, roleName, []as.Privilege{priv})
+	if err != nil && !err.Matches(astypes.INVALID_ROLE) {
+		resp.Diagnostics.AddError("Error revoking privilege", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "revoked privilege "+privToStr(priv)+" from role "+roleName)
+}
+
+// ImportState expects an id of the form role:privilege, role:privilege:namespace or role:privilege:namespace:set.
+// checkProviderRole returns an error if revoking the privilege in data could lock the provider user out.
+func (r *AerospikeRolePrivilege) checkProviderRole(ctx context.Context, data AerospikeRolePrivilegeResourceModel) diag.Diagnostics {
+	roleName := data.Role_name.ValueString()
+	priv := asPrivFromStringValues(data.Privilege, data.Namespace, data.Set)
+
+	return r.asConn.checkProviderRole(ctx, roleName, "Revoking privilege "+privToStr(priv)+" from role "+roleName)
+}
+
+func (r *AerospikeRolePrivilege) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, ":")
+	if len(parts) < 2 || len(parts) > 4 || sliceutil.Contains(parts, "") {
+		resp.Diagnostics.AddError("Invalid import id", "Expected role:privilege[:namespace[:set]], got "+req.ID)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_name"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("privilege"), parts[1])...)
+	if len(parts) > 2 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), parts[2])...)
+	}
+	if len(parts) > 3 {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("set"), parts[3])...)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeRolePrivilege(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeRolePrivilegeConfig("read"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_role_privilege.testroleprivilege1", "role_name", "testroleprivilege1"),
+					resource.TestCheckResourceAttr("aerospike_role_privilege.testroleprivilege1", "privilege", "read"),
+				),
+			},
+			// replace the privilege
+			{
+				Config: testAccAerospikeRolePrivilegeConfig("write"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_role_privilege.testroleprivilege1", "privilege", "write"),
+				),
+			},
+			// import
+			{
+				ResourceName:                         "aerospike_role_privilege.testroleprivilege1",
+				ImportState:                          true,
+				ImportStateId:                        "testroleprivilege1:write:aerospike:test",
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "role_name",
+			},
+		},
+	})
+}
+
+func testAccAerospikeRolePrivilegeConfig(privilege string) string {
+	return fmt.Sprintf(`
+resource "aerospike_role" "testroleprivilege1" {
+  role_name  = "testroleprivilege1"
+  privileges = [{ privilege = "sys-admin" }]
+
+  lifecycle {
+    ignore_changes = [privileges]
+  }
+}
+
+resource "aerospike_role_privilege" "testroleprivilege1" {
+  role_name = aerospike_role.testroleprivilege1.role_name
+  privilege = "%s"
+  namespace = "aerospike"
+  set       = "test"
+}`, privilege)
+}
@@ -4,9 +4,15 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
 
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -20,7 +26,7 @@ func TestAccAerospikeRole(t *testing.T) {
 				Config: testAccAerospikeRoleConfig("testrole1", "[{privilege=\"read\"}]", "[\"1.1.1.1\"]"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("aerospike_role.testrole1", "role_name", "testrole1"),
-					resource.TestCheckResourceAttr("aerospike_role.testrole1", "white_list.0", "1.1.1.1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole1", "white_list.*", "1.1.1.1"),
 				),
 			},
 			// update privs
@@ -28,7 +34,7 @@ func TestAccAerospikeRole(t *testing.T) {
 				Config: testAccAerospikeRoleConfig("testrole1", "[{privilege=\"write\",namespace=\"aerospike\",set=\"test\"}]", "[\"1.1.1.1\"]"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("aerospike_role.testrole1", "role_name", "testrole1"),
-					resource.TestCheckResourceAttr("aerospike_role.testrole1", "white_list.0", "1.1.1.1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole1", "white_list.*", "1.1.1.1"),
 				),
 			},
 			// update white list
@@ -36,7 +42,104 @@ func TestAccAerospikeRole(t *testing.T) {
 				Config: testAccAerospikeRoleConfig("testrole1", "[{privilege=\"write\",namespace=\"aerospike\",set=\"test\"}]", "[\"2.2.2.2\"]"),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("aerospike_role.testrole1", "role_name", "testrole1"),
-					resource.TestCheckResourceAttr("aerospike_role.testrole1", "white_list.0", "2.2.2.2"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole1", "white_list.*", "2.2.2.2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAerospikeRoleQuotaOutOfRange(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_role" "testrole2" {
+  role_name  = "testrole2"
+  privileges = [{privilege="read"}]
+  read_quota = 4294967296
+}`,
+				ExpectError: regexp.MustCompile(`read_quota`),
+			},
+			{
+				Config: `
+resource "aerospike_role" "testrole2" {
+  role_name   = "testrole2"
+  privileges  = [{privilege="read"}]
+  write_quota = -1
+}`,
+				ExpectError: regexp.MustCompile(`write_quota`),
+			},
+		},
+	})
+}
+
+func TestAccAerospikeRoleInvalidName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAerospikeRoleConfig("read-write", "[{privilege=\"read\"}]", "[]"),
+				ExpectError: regexp.MustCompile(`role_name`),
+			},
+			{
+				Config: `
+resource "aerospike_role" "testrole3" {
+  role_name  = "bad;name"
+  privileges = [{privilege="read"}]
+}`,
+				ExpectError: regexp.MustCompile(`must not contain control characters`),
+			},
+		},
+	})
+}
+
+func TestAccAerospikeRoleResolveWhiteList(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "aerospike_role" "testrole3" {
+  role_name          = "testrole3"
+  privileges         = [{privilege="read"}]
+  white_list         = ["localhost", "10.0.0.0/8"]
+  resolve_white_list = true
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole3", "white_list.*", "localhost"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole3", "resolved_white_list.*", "127.0.0.1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole3", "resolved_white_list.*", "10.0.0.0/8"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAerospikeRoleUnknownWhiteList(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// the white list is only known at apply time
+				Config: `
+resource "terraform_data" "address" {
+  input = "10.0.0.1"
+}
+
+resource "aerospike_role" "testrole4" {
+  role_name  = "testrole4"
+  privileges = [{privilege="read"}]
+  white_list = [terraform_data.address.output]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole4", "white_list.*", "10.0.0.1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_role.testrole4", "resolved_white_list.*", "10.0.0.1"),
 				),
 			},
 		},
@@ -51,3 +154,106 @@ resource "aerospike_role" "%[1]s" {
   white_list  = %[3]s
 }`, roleName, privileges, white_list)
 }
+
+func TestSyncPrivileges(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeRole{asConn: newFakeConnection(client, "admin")}
+
+	read := as.Privilege{Code: as.Read, Namespace: "test"}
+	write := as.Privilege{Code: as.Write, Namespace: "test", SetName: "s1"}
+	sysAdmin := as.Privilege{Code: as.SysAdmin}
+
+	client.roles["role1"] = &as.Role{Name: "role1", Privileges: []as.Privilege{read, write}}
+
+	diags := r.syncPrivileges(context.Background(), "role1", []as.Privilege{read, write}, []as.Privilege{read, sysAdmin})
+	if diags.HasError() {
+		t.Fatalf("syncPrivileges() returned errors: %v", diags)
+	}
+
+	if want := []as.Privilege{read, sysAdmin}; !reflect.DeepEqual(client.roles["role1"].Privileges, want) {
+		t.Errorf("privileges = %v, want %v", client.roles["role1"].Privileges, want)
+	}
+	if want := []string{"GrantPrivileges role1", "RevokePrivileges role1"}; !reflect.DeepEqual(client.sortedCalls(), want) {
+		t.Errorf("calls = %v, want %v", client.sortedCalls(), want)
+	}
+
+	// no changes, no calls
+	client.calls = nil
+	diags = r.syncPrivileges(context.Background(), "role1", []as.Privilege{read, sysAdmin}, []as.Privilege{sysAdmin, read})
+	if diags.HasError() {
+		t.Fatalf("syncPrivileges() returned errors: %v", diags)
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls = %v, want none", client.calls)
+	}
+}
+
+func TestSyncPrivilegesProviderRole(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeRole{asConn: newFakeConnection(client, "admin")}
+
+	read := as.Privilege{Code: as.Read, Namespace: "test"}
+	userAdmin := as.Privilege{Code: as.UserAdmin}
+	client.roles["admins"] = &as.Role{Name: "admins", Privileges: []as.Privilege{read, userAdmin}}
+	client.users["admin"] = &as.UserRoles{User: "admin", Roles: []string{"admins"}}
+
+	diags := r.syncPrivileges(context.Background(), "admins", []as.Privilege{read, userAdmin}, []as.Privilege{read})
+	if !diags.HasError() {
+		t.Errorf("syncPrivileges() revoking privileges from a role of the provider user succeeded, want an error")
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls = %v, want none", client.calls)
+	}
+
+	// granting is allowed
+	if diags := r.syncPrivileges(context.Background(), "admins", []as.Privilege{read}, []as.Privilege{read, userAdmin}); diags.HasError() {
+		t.Errorf("syncPrivileges() granting privileges to a role of the provider user returned errors: %v", diags)
+	}
+}
+
+func TestCheckProviderRole(t *testing.T) {
+	client := newFakeClient()
+	conn := newFakeConnection(client, "admin")
+	client.users["admin"] = &as.UserRoles{User: "admin", Roles: []string{"admins"}}
+
+	if diags := conn.checkProviderRole(context.Background(), "admins", "Dropping role admins"); !diags.HasError() {
+		t.Errorf("checkProviderRole() of a role of the provider user succeeded, want an error")
+	}
+	if diags := conn.checkProviderRole(context.Background(), "others", "Dropping role others"); diags.HasError() {
+		t.Errorf("checkProviderRole() of another role returned errors: %v", diags)
+	}
+
+	// users the server doesn't know, e.g. external ones, can't be checked
+	delete(client.users, "admin")
+	if diags := conn.checkProviderRole(context.Background(), "admins", "Dropping role admins"); diags.HasError() {
+		t.Errorf("checkProviderRole() for an unknown provider user returned errors: %v", diags)
+	}
+}
+
+func TestPrivilegeNormalization(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeRole{asConn: newFakeConnection(client, "admin")}
+
+	read := as.Privilege{Code: as.Read, Namespace: "test"}
+	client.roles["role1"] = &as.Role{Name: "role1", Privileges: []as.Privilege{read}}
+
+	// entries that only differ in case or whitespace are the same privilege
+	planned := []as.Privilege{
+		asPrivFromStringValues(types.StringValue(" Read"), types.StringValue("test "), types.StringNull()),
+		asPrivFromStringValues(types.StringValue("read"), types.StringValue("test"), types.StringNull()),
+	}
+	if want := []as.Privilege{read}; !reflect.DeepEqual(dedupePrivileges(planned), want) {
+		t.Errorf("dedupePrivileges() = %v, want %v", dedupePrivileges(planned), want)
+	}
+	if !samePrivileges(planned, []as.Privilege{read}) {
+		t.Errorf("samePrivileges(%v, %v) = false, want true", planned, []as.Privilege{read})
+	}
+
+	diags := r.syncPrivileges(context.Background(), "role1", []as.Privilege{read}, planned)
+	if diags.HasError() {
+		t.Fatalf("syncPrivileges() returned errors: %v", diags)
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls = %v, want none", client.calls)
+	}
+}
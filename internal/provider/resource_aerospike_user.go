@@ -6,12 +6,13 @@ package provider
 import (
 	"context"
 	"fmt"
-	as "github.com/aerospike/aerospike-client-go/v7"
 	astypes "github.com/aerospike/aerospike-client-go/v7/types"
 	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -24,6 +25,11 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AerospikeUser{}
 var _ resource.ResourceWithImportState = &AerospikeUser{}
+var _ resource.ResourceWithUpgradeState = &AerospikeUser{}
+var _ resource.ResourceWithModifyPlan = &AerospikeUser{}
+
+// defaultAdminUser is the user every cluster is created with.
+const defaultAdminUser = "admin"
 
 func NewAerospikeUser() resource.Resource {
 	return &AerospikeUser{}
@@ -39,6 +45,11 @@ type AerospikeUserModel struct {
 	User_name types.String   `tfsdk:"user_name"`
 	Password  types.String   `tfsdk:"password"`
 	Roles     []types.String `tfsdk:"roles"`
+
+	Deletion_protection types.Bool `tfsdk:"deletion_protection"`
+	Adopt_existing      types.Bool `tfsdk:"adopt_existing"`
+
+	Connection types.Object `tfsdk:"connection"`
 }
 
 func (r *AerospikeUser) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -49,29 +60,120 @@ func (r *AerospikeUser) Schema(ctx context.Context, req resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Aerospike user",
+		// Version 1 changed roles from a list to a set
+		Version: 1,
 
 		Attributes: map[string]schema.Attribute{
 			"user_name": schema.StringAttribute{
 				Description: "User name",
 				Required:    true,
+				Validators:  securityNameValidators(),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"password": schema.StringAttribute{
-				Description: "Password",
+				Description: fmt.Sprintf("Password, up to %d bytes", maxPasswordLength),
 				Required:    true,
 				Sensitive:   true,
+				Validators:  passwordValidators(),
 			},
-			"roles": schema.ListAttribute{
+			"roles": schema.SetAttribute{
 				Description: "Roles that should be granted to the user",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"deletion_protection": schema.BoolAttribute{
+				Description: "Prevent the user from being dropped while set to true",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "If the user already exists when it's created, take it over and set its password and roles instead of failing. " +
+					"The existing user's password is changed, so clients that log in with the old password are locked out",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"connection": connectionAttribute(),
 		},
 	}
 }
 
+func (r *AerospikeUser) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// roles was a list in version 0. The model reads both lists and sets, so the state is copied as is
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"user_name": schema.StringAttribute{
+						Required: true,
+					},
+					"password": schema.StringAttribute{
+						Required:  true,
+						Sensitive: true,
+					},
+					"roles": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"deletion_protection": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					"adopt_existing": schema.BoolAttribute{
+						Optional: true,
+						Computed: true,
+					},
+					// connection is listed so the state decodes into the current model, version 0 states have it as null
+					"connection": connectionAttribute(),
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var data AerospikeUserModel
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				if data.Deletion_protection.IsNull() {
+					data.Deletion_protection = types.BoolValue(false)
+				}
+				if data.Adopt_existing.IsNull() {
+					data.Adopt_existing = types.BoolValue(false)
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
+// ModifyPlan rejects creating the default admin user, which already exists on every cluster, unless it is adopted.
+// Users already in the state, e.g. imported ones, aren't created and are left alone.
+func (r *AerospikeUser) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var userName types.String
+	var adoptExisting types.Bool
+
+	// Only creates are checked
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("user_name"), &userName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("adopt_existing"), &adoptExisting)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if userName.ValueString() == defaultAdminUser && !adoptExisting.IsUnknown() && !adoptExisting.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("user_name"), "Reserved user name",
+			"The "+defaultAdminUser+" user exists on every cluster and can't be created. Set adopt_existing = true to manage its password and roles")
+	}
+}
+
 func (r *AerospikeUser) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -89,10 +191,21 @@ func (r *AerospikeUser) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
 	r.asConn = asConn
 }
 
 func (r *AerospikeUser) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_user")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data AerospikeUserModel
 
 	// Read Terraform plan data into the model
@@ -102,16 +215,33 @@ func (r *AerospikeUser) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
-
-	tmpRoles := make([]string, 0)
-	for _, r := range data.Roles {
-		tmpRoles = append(tmpRoles, r.ValueString())
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	err := (*r.asConn.client).CreateUser(adminPol, data.User_name.ValueString(), data.Password.ValueString(), tmpRoles)
+	adminPol := r.asConn.adminPolicy
+
+	tmpRoles := rolesToStrings(data.Roles)
+
+	err := r.asConn.getClient(ctx).CreateUser(adminPol, data.User_name.ValueString(), data.Password.ValueString(), tmpRoles)
 	if err != nil {
-		panic(err)
+		switch {
+		case err.Matches(astypes.USER_ALREADY_EXISTS) && data.Adopt_existing.ValueBool():
+			resp.Diagnostics.Append(r.adoptUser(ctx, data.User_name.ValueString(), data.Password.ValueString(), tmpRoles)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		case err.Matches(astypes.USER_ALREADY_EXISTS):
+			resp.Diagnostics.Append(diag.NewErrorDiagnostic("User already exists",
+				"User that was being created already exists: "+data.User_name.ValueString()+". Import it or set adopt_existing"))
+			return
+		default:
+			resp.Diagnostics.AddError("Error creating user", err.Error())
+			return
+		}
 	}
 
 	// Write logs using the tflog package
@@ -131,11 +261,19 @@ func (r *AerospikeUser) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	adminPol := r.asConn.adminPolicy
 
-	tmpRoles, err := (*r.asConn.client).QueryUser(adminPol, data.User_name.ValueString())
+	tmpRoles, err := r.asConn.getClient(ctx).QueryUser(adminPol, data.User_name.ValueString())
 	if err != nil && !err.Matches(astypes.INVALID_USER) {
-		panic(err)
+		resp.Diagnostics.AddError("Error reading user", err.Error())
+		return
 	}
 
 	if err != nil && err.Matches(astypes.INVALID_USER) {
@@ -150,6 +288,13 @@ func (r *AerospikeUser) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	if data.Deletion_protection.IsNull() {
+		data.Deletion_protection = types.BoolValue(false)
+	}
+	if data.Adopt_existing.IsNull() {
+		data.Adopt_existing = types.BoolValue(false)
+	}
+
 	data.Roles = nil
 	// Aerospike returns a one item array with "" for no roles, ignore just this case
 	if len(tmpRoles.Roles) >= 1 && tmpRoles.Roles[0] != "" {
@@ -165,6 +310,11 @@ func (r *AerospikeUser) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 func (r *AerospikeUser) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_user")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var plan, state, data AerospikeUserModel
 
 	// Read Terraform plan data into the model
@@ -175,55 +325,36 @@ func (r *AerospikeUser) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, plan.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	data.User_name = plan.User_name
 	data.Password = plan.Password
+	data.Deletion_protection = plan.Deletion_protection
+	data.Adopt_existing = plan.Adopt_existing
 
 	if !plan.Password.Equal(state.Password) {
-		adminPol := as.NewAdminPolicy()
-		err := (*r.asConn.client).ChangePassword(adminPol, plan.User_name.ValueString(), plan.Password.ValueString())
+		adminPol := r.asConn.adminPolicy
+		err := r.asConn.getClient(ctx).ChangePassword(adminPol, plan.User_name.ValueString(), plan.Password.ValueString())
 		if err != nil {
-			panic(err)
+			resp.Diagnostics.AddError("Error changing password", err.Error())
+			return
 		}
 		tflog.Trace(ctx, "Changed password for "+data.User_name.ValueString())
 	}
 
-	planRoles := make([]string, 0)
-	for _, r := range plan.Roles {
-		planRoles = append(planRoles, r.ValueString())
-	}
-	sort.Strings(planRoles)
-
-	stateRoles := make([]string, 0)
-	for _, r := range state.Roles {
-		stateRoles = append(stateRoles, r.ValueString())
-	}
-	sort.Strings(stateRoles)
-
 	data.Roles = state.Roles
 
-	if !reflect.DeepEqual(planRoles, stateRoles) {
-		// change in roles
-		tflog.Trace(ctx, "Diff in roles, plan: "+strings.Join(planRoles, ", ")+", state: "+strings.Join(stateRoles, ", "))
-		intersection := sliceutil.IntersectStrings(stateRoles, planRoles)
-		rolesToAdd := sliceutil.Stringify(sliceutil.Difference(planRoles, intersection))
-		rolesToRevoke := sliceutil.Stringify(sliceutil.Difference(stateRoles, intersection))
-		tflog.Trace(ctx, "Roles to add: "+strings.Join(rolesToAdd, ", "))
-		tflog.Trace(ctx, "Roles to revoke: "+strings.Join(rolesToRevoke, ", "))
-
-		adminPol := as.NewAdminPolicy()
-
-		if len(rolesToAdd) > 0 {
-			err := (*r.asConn.client).GrantRoles(adminPol, plan.User_name.ValueString(), rolesToAdd)
-			if err != nil {
-				panic(err)
-			}
-		}
-		if len(rolesToRevoke) > 0 {
-			err := (*r.asConn.client).RevokeRoles(adminPol, plan.User_name.ValueString(), rolesToRevoke)
-			if err != nil {
-				panic(err)
-			}
-		}
+	changed, diags := r.syncRoles(ctx, plan.User_name.ValueString(), rolesToStrings(state.Roles), rolesToStrings(plan.Roles))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if changed {
 		data.Roles = plan.Roles
 	}
 
@@ -232,6 +363,11 @@ func (r *AerospikeUser) Update(ctx context.Context, req resource.UpdateRequest,
 }
 
 func (r *AerospikeUser) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_user")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data AerospikeUserModel
 
 	// Read Terraform prior state data into the model
@@ -241,17 +377,142 @@ func (r *AerospikeUser) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	adminPol := as.NewAdminPolicy()
+	// manage the resource on the cluster of its connection block, if set
+	r, diags := r.withConnection(ctx, data.Connection)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Deletion_protection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("deletion_protection"), "Deletion protection enabled",
+			"User "+data.User_name.ValueString()+" has deletion_protection set. Set it to false and apply before destroying the user")
+		return
+	}
 
-	err := (*r.asConn.client).DropUser(adminPol, data.User_name.ValueString())
+	if r.isProviderUser(data.User_name.ValueString()) {
+		resp.Diagnostics.AddError("Can't drop the provider user",
+			"User "+data.User_name.ValueString()+" is the user the provider connects with. Dropping it would lock the provider out of the cluster. "+
+				"Remove it from the terraform state instead or configure the provider with a different user")
+		return
+	}
+
+	resp.Diagnostics.Append(r.asConn.checkDestructive("Dropping user " + data.User_name.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	adminPol := r.asConn.adminPolicy
+
+	err := r.asConn.getClient(ctx).DropUser(adminPol, data.User_name.ValueString())
 	if err != nil && !err.Matches(astypes.INVALID_USER) {
-		panic(err)
+		resp.Diagnostics.AddError("Error dropping user", err.Error())
+		return
 	}
 
 	// Write logs using the tflog package
 	tflog.Trace(ctx, "dropped user "+data.User_name.ValueString())
 }
 
+// adoptUser takes over an existing user, setting its password and roles to the planned values.
+func (r *AerospikeUser) adoptUser(ctx context.Context, userName, password string, planRoles []string) diag.Diagnostics {
+	adminPol := r.asConn.adminPolicy
+
+	tflog.Trace(ctx, "user "+userName+" already exists, adopting it")
+
+	err := r.asConn.getClient(ctx).ChangePassword(adminPol, userName, password)
+	if err != nil {
+		return diag.Diagnostics{diag.NewErrorDiagnostic("Error adopting user", err.Error())}
+	}
+
+	current, err := r.asConn.getClient(ctx).QueryUser(adminPol, userName)
+	if err != nil {
+		return diag.Diagnostics{diag.NewErrorDiagnostic("Error adopting user", err.Error())}
+	}
+
+	currentRoles := make([]string, 0)
+	// Aerospike returns a one item array with "" for no roles, ignore just this case
+	if len(current.Roles) >= 1 && current.Roles[0] != "" {
+		currentRoles = append(currentRoles, current.Roles...)
+	}
+
+	_, diags := r.syncRoles(ctx, userName, currentRoles, planRoles)
+
+	return diags
+}
+
+// syncRoles grants and revokes roles so the user ends up with exactly planRoles. It reports whether anything changed.
+func (r *AerospikeUser) syncRoles(ctx context.Context, userName string, currentRoles, planRoles []string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sort.Strings(planRoles)
+	sort.Strings(currentRoles)
+
+	if reflect.DeepEqual(planRoles, currentRoles) {
+		return false, diags
+	}
+
+	// change in roles
+	tflog.Trace(ctx, "Diff in roles, plan: "+strings.Join(planRoles, ", ")+", state: "+strings.Join(currentRoles, ", "))
+	intersection := sliceutil.IntersectStrings(currentRoles, planRoles)
+	rolesToAdd := sliceutil.Stringify(sliceutil.Difference(planRoles, intersection))
+	rolesToRevoke := sliceutil.Stringify(sliceutil.Difference(currentRoles, intersection))
+	tflog.Trace(ctx, "Roles to add: "+strings.Join(rolesToAdd, ", "))
+	tflog.Trace(ctx, "Roles to revoke: "+strings.Join(rolesToRevoke, ", "))
+
+	if len(rolesToRevoke) > 0 && r.isProviderUser(userName) {
+		diags.AddAttributeError(path.Root("roles"), "Can't revoke roles from the provider user",
+			"User "+userName+" is the user the provider connects with. Revoking its roles ("+
+				strings.Join(rolesToRevoke, ", ")+") could lock the provider out of the cluster")
+		return false, diags
+	}
+
+	adminPol := r.asConn.adminPolicy
+
+	if len(rolesToAdd) > 0 {
+		err := r.asConn.getClient(ctx).GrantRoles(adminPol, userName, rolesToAdd)
+		if err != nil {
+			diags.AddError("Error granting roles", err.Error())
+			return false, diags
+		}
+	}
+	if len(rolesToRevoke) > 0 {
+		err := r.asConn.getClient(ctx).RevokeRoles(adminPol, userName, rolesToRevoke)
+		if err != nil {
+			diags.AddError("Error revoking roles", err.Error())
+			return false, diags
+		}
+	}
+
+	return true, diags
+}
+
+func rolesToStrings(roles []types.String) []string {
+	tmpRoles := make([]string, 0)
+	for _, r := range roles {
+		tmpRoles = append(tmpRoles, r.ValueString())
+	}
+
+	return tmpRoles
+}
+
+// isProviderUser reports whether userName is the user the provider authenticated with.
+func (r *AerospikeUser) isProviderUser(userName string) bool {
+	return r.asConn.userName != "" && r.asConn.userName == userName
+}
+
 func (r *AerospikeUser) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("user_name"), req, resp)
 }
+
+// withConnection returns the resource bound to the cluster of its connection block, or to the provider's cluster
+// when the block isn't set. Both need security enabled.
+func (r *AerospikeUser) withConnection(ctx context.Context, connection types.Object) (*AerospikeUser, diag.Diagnostics) {
+	conn, diags := r.asConn.overrideConnection(ctx, connection, "aerospike_user")
+	// overrideConnection checks the cluster of the block, the provider's cluster only matters without one
+	if connection.IsNull() {
+		diags.Append(conn.requireSecurity("aerospike_user")...)
+	}
+
+	return &AerospikeUser{asConn: conn}, diags
+}
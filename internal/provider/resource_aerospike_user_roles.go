@@ -0,0 +1,282 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/ghetzel/go-stockutil/sliceutil"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeUserRoles{}
+var _ resource.ResourceWithImportState = &AerospikeUserRoles{}
+
+func NewAerospikeUserRoles() resource.Resource {
+	return &AerospikeUserRoles{}
+}
+
+// AerospikeUserRoles defines the resource implementation.
+type AerospikeUserRoles struct {
+	asConn *asConnection
+}
+
+// AerospikeUserRolesModel describes the resource data model.
+type AerospikeUserRolesModel struct {
+	User_name types.String   `tfsdk:"user_name"`
+	Roles     []types.String `tfsdk:"roles"`
+}
+
+func (r *AerospikeUserRoles) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_roles"
+}
+
+func (r *AerospikeUserRoles) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Grants roles to an existing user without managing the user itself. Only the roles listed here are " +
+			"granted and revoked, roles granted by other means are left alone. Don't set roles on an aerospike_user that is " +
+			"also managed with this resource",
+
+		Attributes: map[string]schema.Attribute{
+			"user_name": schema.StringAttribute{
+				Description: "User name",
+				Required:    true,
+				Validators:  securityNameValidators(),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"roles": schema.SetAttribute{
+				Description: "Roles to grant to the user",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeUserRoles) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(asConn.requireSecurity("aerospike_user_roles")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeUserRoles) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_user_roles")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeUserRolesModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userName := data.User_name.ValueString()
+	roles := rolesToStrings(data.Roles)
+
+	err := r.asConn.getClient(ctx).GrantRoles(r.asConn.adminPolicy, userName, roles)
+	if err != nil {
+		if err.Matches(astypes.INVALID_USER) {
+			resp.Diagnostics.AddAttributeError(path.Root("user_name"), "User does not exist",
+				"User "+userName+" does not exist. Create it before granting it roles")
+			return
+		}
+		resp.Diagnostics.AddError("Error granting roles", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "granted roles "+strings.Join(roles, ", ")+" to user "+userName)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeUserRoles) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeUserRolesModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userName := data.User_name.ValueString()
+
+	user, err := r.asConn.getClient(ctx).QueryUser(r.asConn.adminPolicy, userName)
+	if err != nil {
+		if err.Matches(astypes.INVALID_USER) {
+			tflog.Trace(ctx, "read roles of user "+userName+" and the user does not exist")
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading user", err.Error())
+		return
+	}
+
+	// Only report the managed roles the user still has. After an import there are no managed roles yet, so take all
+	// of them
+	imported := data.Roles == nil
+	managedRoles := rolesToStrings(data.Roles)
+	data.Roles = nil
+	for _, role := range user.Roles {
+		if role == "" {
+			continue
+		}
+		if imported || sliceutil.Contains(managedRoles, role) {
+			data.Roles = append(data.Roles, types.StringValue(role))
+		}
+	}
+
+	if len(data.Roles) == 0 {
+		tflog.Trace(ctx, "user "+userName+" has none of the managed roles")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(ctx, "read roles of user "+userName)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeUserRoles) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_user_roles")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state AerospikeUserRolesModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userName := plan.User_name.ValueString()
+	planRoles := rolesToStrings(plan.Roles)
+	stateRoles := rolesToStrings(state.Roles)
+
+	intersection := sliceutil.IntersectStrings(stateRoles, planRoles)
+	rolesToAdd := sliceutil.Stringify(sliceutil.Difference(planRoles, intersection))
+	rolesToRevoke := sliceutil.Stringify(sliceutil.Difference(stateRoles, intersection))
+
+	if len(rolesToAdd) > 0 {
+		err := r.asConn.getClient(ctx).GrantRoles(r.asConn.adminPolicy, userName, rolesToAdd)
+		if err != nil {
+			resp.Diagnostics.AddError("Error granting roles", err.Error())
+			return
+		}
+	}
+	if len(rolesToRevoke) > 0 {
+		if r.isProviderUser(userName) {
+			resp.Diagnostics.AddAttributeError(path.Root("roles"), "Can't revoke roles from the provider user",
+				"User "+userName+" is the user the provider connects with. Revoking its roles ("+
+					strings.Join(rolesToRevoke, ", ")+") could lock the provider out of the cluster")
+			return
+		}
+		err := r.asConn.getClient(ctx).RevokeRoles(r.asConn.adminPolicy, userName, rolesToRevoke)
+		if err != nil {
+			resp.Diagnostics.AddError("Error revoking roles", err.Error())
+			return
+		}
+	}
+
+	tflog.Trace(ctx, "updated roles of user "+userName+", added: "+strings.Join(rolesToAdd, ", ")+
+		", revoked: "+strings.Join(rolesToRevoke, ", "))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *AerospikeUserRoles) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_user_roles")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeUserRolesModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userName := data.User_name.ValueString()
+	roles := rolesToStrings(data.Roles)
+
+	if r.isProviderUser(userName) {
+		resp.Diagnostics.AddError("Can't revoke roles from the provider user",
+			"User "+userName+" is the user the provider connects with. Revoking its roles could lock the provider out of the cluster. "+
+				"Remove the resource from the terraform state instead")
+		return
+	}
+
+	err := r.asConn.getClient(ctx).RevokeRoles(r.asConn.adminPolicy, userName, roles)
+	if err != nil && !err.Matches(astypes.INVALID_USER) {
+		resp.Diagnostics.AddError("Error revoking roles", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "revoked roles "+strings.Join(roles, ", ")+" from user "+userName)
+}
+
+// ImportState imports all the roles the user currently has.
+func (r *AerospikeUserRoles) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("user_name"), req, resp)
+}
+
+func (r *AerospikeUserRoles) isProviderUser(userName string) bool {
+	return r.asConn.userName != "" && r.asConn.userName == userName
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeUserRoles(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccAerospikeUserRolesConfig("\"read\""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_user_roles.testuserroles1", "user_name", "testuserroles1"),
+					resource.TestCheckResourceAttr("aerospike_user_roles.testuserroles1", "roles.#", "1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_user_roles.testuserroles1", "roles.*", "read"),
+				),
+			},
+			// add a role
+			{
+				Config: testAccAerospikeUserRolesConfig("\"read\", \"write\""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_user_roles.testuserroles1", "roles.#", "2"),
+					resource.TestCheckTypeSetElemAttr("aerospike_user_roles.testuserroles1", "roles.*", "write"),
+				),
+			},
+			// revoke a role
+			{
+				Config: testAccAerospikeUserRolesConfig("\"write\""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_user_roles.testuserroles1", "roles.#", "1"),
+					resource.TestCheckTypeSetElemAttr("aerospike_user_roles.testuserroles1", "roles.*", "write"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAerospikeUserRolesConfig(roles string) string {
+	return fmt.Sprintf(`
+resource "aerospike_user" "testuserroles1" {
+  user_name = "testuserroles1"
+  password  = "testpass1"
+
+  lifecycle {
+    ignore_changes = [roles]
+  }
+}
+
+resource "aerospike_user_roles" "testuserroles1" {
+  user_name = aerospike_user.testuserroles1.user_name
+  roles     = [%s]
+}`, roles)
+}
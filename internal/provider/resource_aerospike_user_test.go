@@ -4,9 +4,20 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
+	as "github.com/aerospike/aerospike-client-go/v7"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
@@ -35,6 +46,31 @@ func TestAccAerospikeUser(t *testing.T) {
 	})
 }
 
+func TestAccAerospikeUserInvalidName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccAerospikeUserConfig("admin", "password", ""),
+				ExpectError: regexp.MustCompile(`Reserved user name`),
+			},
+			{
+				Config: `
+resource "aerospike_user" "long" {
+  user_name = "` + strings.Repeat("u", maxSecurityNameLength+1) + `"
+  password  = "password"
+}`,
+				ExpectError: regexp.MustCompile(`user_name`),
+			},
+			{
+				Config:      testAccAerospikeUserConfig("testuser3", strings.Repeat("p", maxPasswordLength+1), ""),
+				ExpectError: regexp.MustCompile(`password`),
+			},
+		},
+	})
+}
+
 func testAccAerospikeUserConfig(userName string, password string, roles string) string {
 	return fmt.Sprintf(`
 resource "aerospike_user" "%[1]s" {
@@ -43,3 +79,156 @@ resource "aerospike_user" "%[1]s" {
   roles = [%[3]s]
 }`, userName, password, roles)
 }
+
+func TestSyncRoles(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeUser{asConn: newFakeConnection(client, "admin")}
+
+	client.users["user1"] = &as.UserRoles{User: "user1", Roles: []string{"role1", "role2"}}
+
+	changed, diags := r.syncRoles(context.Background(), "user1", []string{"role2", "role1"}, []string{"role3", "role1"})
+	if diags.HasError() {
+		t.Fatalf("syncRoles() returned errors: %v", diags)
+	}
+	if !changed {
+		t.Errorf("syncRoles() changed = false, want true")
+	}
+
+	roles := append([]string{}, client.users["user1"].Roles...)
+	sort.Strings(roles)
+	if want := []string{"role1", "role3"}; !reflect.DeepEqual(roles, want) {
+		t.Errorf("roles = %v, want %v", roles, want)
+	}
+
+	// same roles in a different order are not a change
+	client.calls = nil
+	changed, diags = r.syncRoles(context.Background(), "user1", []string{"role3", "role1"}, []string{"role1", "role3"})
+	if diags.HasError() || changed || len(client.calls) != 0 {
+		t.Errorf("syncRoles() with equal roles: changed = %v, calls = %v, diags = %v", changed, client.calls, diags)
+	}
+}
+
+func TestWithConnectionRequiresSecurity(t *testing.T) {
+	conn := newFakeConnection(newFakeClient(), "admin")
+	conn.securityEnabled = false
+	r := &AerospikeUser{asConn: conn}
+
+	if _, diags := r.withConnection(context.Background(), types.ObjectNull(nil)); !diags.HasError() {
+		t.Errorf("withConnection() without a connection block on a cluster without security succeeded, want an error")
+	}
+
+	// the cluster of a connection block is checked when it is known, not the provider's
+	if _, diags := r.withConnection(context.Background(), types.ObjectUnknown(nil)); diags.HasError() {
+		t.Errorf("withConnection() with an unknown connection block returned errors: %v", diags)
+	}
+}
+
+func TestSyncRolesProviderUser(t *testing.T) {
+	client := newFakeClient()
+	r := &AerospikeUser{asConn: newFakeConnection(client, "admin")}
+
+	client.users["admin"] = &as.UserRoles{User: "admin", Roles: []string{"user-admin", "sys-admin"}}
+
+	_, diags := r.syncRoles(context.Background(), "admin", []string{"user-admin", "sys-admin"}, []string{"sys-admin"})
+	if !diags.HasError() {
+		t.Errorf("syncRoles() revoking roles from the provider user succeeded, want an error")
+	}
+	if len(client.calls) != 0 {
+		t.Errorf("calls = %v, want none", client.calls)
+	}
+}
+
+// userState returns data as the state of an aerospike_user, to call its CRUD methods directly. Plans are built from
+// the same schema and value.
+func userState(t *testing.T, r *AerospikeUser, data AerospikeUserModel) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	if data.Connection.IsNull() {
+		data.Connection = types.ObjectNull(connectionAttribute().GetType().(types.ObjectType).AttrTypes)
+	}
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, &data); diags.HasError() {
+		t.Fatalf("setting the state: %v", diags)
+	}
+
+	return state
+}
+
+func TestUserDeleteProtected(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	r := &AerospikeUser{asConn: newFakeConnection(client, "admin")}
+
+	client.users["user1"] = &as.UserRoles{User: "user1"}
+	data := AerospikeUserModel{
+		User_name:           types.StringValue("user1"),
+		Password:            types.StringValue("password"),
+		Deletion_protection: types.BoolValue(true),
+		Adopt_existing:      types.BoolValue(false),
+	}
+
+	var resp fwresource.DeleteResponse
+	r.Delete(ctx, fwresource.DeleteRequest{State: userState(t, r, data)}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("Delete() of a user with deletion_protection succeeded, want an error")
+	}
+	if _, ok := client.users["user1"]; !ok || len(client.calls) != 0 {
+		t.Errorf("users = %v, calls = %v, want user1 kept and no calls", client.users, client.calls)
+	}
+
+	data.Deletion_protection = types.BoolValue(false)
+	resp = fwresource.DeleteResponse{}
+	r.Delete(ctx, fwresource.DeleteRequest{State: userState(t, r, data)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete() without deletion_protection returned errors: %v", resp.Diagnostics)
+	}
+	if _, ok := client.users["user1"]; ok {
+		t.Errorf("Delete() without deletion_protection didn't drop user1")
+	}
+}
+
+func TestUserCreateExisting(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	r := &AerospikeUser{asConn: newFakeConnection(client, "admin")}
+
+	client.users["user1"] = &as.UserRoles{User: "user1", Roles: []string{"old-role"}}
+	data := AerospikeUserModel{
+		User_name:           types.StringValue("user1"),
+		Password:            types.StringValue("new-password"),
+		Roles:               []types.String{types.StringValue("new-role")},
+		Deletion_protection: types.BoolValue(false),
+		Adopt_existing:      types.BoolValue(false),
+	}
+
+	// without adopt_existing the existing user is left alone
+	state := userState(t, r, data)
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: state.Schema, Raw: tftypes.NewValue(state.Raw.Type(), nil)}}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("Create() of an existing user without adopt_existing succeeded, want an error")
+	}
+	if want := []string{"CreateUser user1"}; !reflect.DeepEqual(client.calls, want) {
+		t.Errorf("calls = %v, want %v", client.calls, want)
+	}
+
+	// with adopt_existing its password is changed and its roles replaced
+	client.calls = nil
+	data.Adopt_existing = types.BoolValue(true)
+	state = userState(t, r, data)
+	resp = fwresource.CreateResponse{State: tfsdk.State{Schema: state.Schema, Raw: tftypes.NewValue(state.Raw.Type(), nil)}}
+	r.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Create() of an existing user with adopt_existing returned errors: %v", resp.Diagnostics)
+	}
+	if len(client.calls) < 2 || client.calls[1] != "ChangePassword user1" {
+		t.Errorf("calls = %v, want the password of user1 changed after CreateUser", client.calls)
+	}
+	if want := []string{"new-role"}; !reflect.DeepEqual(client.users["user1"].Roles, want) {
+		t.Errorf("roles = %v, want %v", client.users["user1"].Roles, want)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"time"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeWaitForMigrations{}
+var _ resource.ResourceWithValidateConfig = &AerospikeWaitForMigrations{}
+
+func NewAerospikeWaitForMigrations() resource.Resource {
+	return &AerospikeWaitForMigrations{}
+}
+
+// AerospikeWaitForMigrations defines the resource implementation.
+type AerospikeWaitForMigrations struct {
+	asConn *asConnection
+}
+
+// AerospikeWaitForMigrationsModel describes the resource data model.
+type AerospikeWaitForMigrationsModel struct {
+	Timeout       types.Int64             `tfsdk:"timeout"`
+	Poll_interval types.Int64             `tfsdk:"poll_interval"`
+	Triggers      map[string]types.String `tfsdk:"triggers"`
+}
+
+func (r *AerospikeWaitForMigrations) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wait_for_migrations"
+}
+
+func (r *AerospikeWaitForMigrations) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "Blocks on create until all the nodes agree on the cluster key and migrations have finished. " +
+			"Use it in depends_on between cluster topology changes and the configuration that should only be applied after them. " +
+			"Destroying the resource does nothing",
+
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.Int64Attribute{
+				Description: fmt.Sprintf("Seconds to wait for migrations. The apply fails if they don't finish in time. Defaults to %d",
+					defaultMigrationsTimeout),
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultMigrationsTimeout),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"poll_interval": schema.Int64Attribute{
+				Description: fmt.Sprintf("Seconds between checks while waiting. Defaults to %d", int64(clusterStablePollInterval/time.Second)),
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(int64(clusterStablePollInterval / time.Second)),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary values that wait again when they change, e.g. the ids of the resources that change the cluster topology",
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeWaitForMigrations) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AerospikeWaitForMigrationsModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Timeout.IsUnknown() || data.Poll_interval.IsUnknown() || data.Timeout.IsNull() || data.Poll_interval.IsNull() {
+		return
+	}
+
+	if data.Poll_interval.ValueInt64() > data.Timeout.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(path.Root("poll_interval"), "Invalid poll_interval",
+			fmt.Sprintf("poll_interval (%d) can't be longer than timeout (%d)", data.Poll_interval.ValueInt64(), data.Timeout.ValueInt64()))
+	}
+}
+
+func (r *AerospikeWaitForMigrations) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_wait_for_migrations is not supported",
+			"aerospike_wait_for_migrations uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeWaitForMigrations) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data AerospikeWaitForMigrationsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := time.Duration(data.Timeout.ValueInt64()) * time.Second
+	pollInterval := time.Duration(data.Poll_interval.ValueInt64()) * time.Second
+	if err := r.asConn.waitForMigrations(ctx, r.asConn.infoPolicy, timeout, pollInterval); err != nil {
+		// the state isn't saved, so the next apply waits again
+		resp.Diagnostics.AddError("Migrations did not complete", err.Error())
+		return
+	}
+
+	tflog.Trace(ctx, "migrations completed")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeWaitForMigrations) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Nothing to read, migrations are only waited for when the resource is created
+}
+
+func (r *AerospikeWaitForMigrations) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data AerospikeWaitForMigrationsModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only timeout or poll_interval changed, they apply to the next wait
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeWaitForMigrations) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Trace(ctx, "removed wait for migrations from state")
+}
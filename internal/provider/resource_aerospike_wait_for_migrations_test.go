@@ -0,0 +1,48 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccAerospikeWaitForMigrations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create waits on a stable test cluster
+			{
+				Config: `
+resource "aerospike_wait_for_migrations" "test" {
+  timeout = 60
+  triggers = {
+    step = "1"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_wait_for_migrations.test", "timeout", "60"),
+					resource.TestCheckResourceAttr("aerospike_wait_for_migrations.test", "poll_interval", "2"),
+				),
+			},
+			// changing a trigger waits again
+			{
+				Config: `
+resource "aerospike_wait_for_migrations" "test" {
+  timeout       = 60
+  poll_interval = 1
+  triggers = {
+    step = "2"
+  }
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("aerospike_wait_for_migrations.test", "poll_interval", "1"),
+					resource.TestCheckResourceAttr("aerospike_wait_for_migrations.test", "triggers.step", "2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,283 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"regexp"
+	"strings"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AerospikeXdrFilter{}
+var _ resource.ResourceWithImportState = &AerospikeXdrFilter{}
+
+func NewAerospikeXdrFilter() resource.Resource {
+	return &AerospikeXdrFilter{}
+}
+
+// AerospikeXdrFilter defines the resource implementation.
+type AerospikeXdrFilter struct {
+	asConn *asConnection
+}
+
+// AerospikeXdrFilterModel describes the resource data model.
+type AerospikeXdrFilterModel struct {
+	Dc         types.String `tfsdk:"dc"`
+	Namespace  types.String `tfsdk:"namespace"`
+	Expression types.String `tfsdk:"expression"`
+}
+
+func (r *AerospikeXdrFilter) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_xdr_filter"
+}
+
+func (r *AerospikeXdrFilter) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		Description: "XDR filter expression of a namespace shipped to a datacenter. Destroying the resource removes the filter",
+
+		Attributes: map[string]schema.Attribute{
+			"dc": schema.StringAttribute{
+				Description: "XDR datacenter name",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expression": schema.StringAttribute{
+				Description: "Base64 encoded filter expression, as returned by the client's expression Base64() method",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9+/]+={0,2}$`), "must be a base64 encoded expression"),
+				},
+			},
+		},
+	}
+}
+
+func (r *AerospikeXdrFilter) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	asConn, ok := req.ProviderData.(*asConnection)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected asConnection, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	// Re-establish the connection if it was lost since the provider was configured
+	if err := asConn.ensureConnected(ctx); err != nil {
+		resp.Diagnostics.AddError("Unable to reconnect to Aerospike", err.Error())
+		return
+	}
+
+	if !asConn.supportsInfo() {
+		resp.Diagnostics.AddError("aerospike_xdr_filter is not supported",
+			"aerospike_xdr_filter uses info commands, which are not available with client_type = \"proxy\" (Aerospike Cloud)")
+		return
+	}
+
+	if !asConn.enterprise {
+		resp.Diagnostics.AddError("Enterprise Edition required",
+			"aerospike_xdr_filter requires Enterprise Edition. The cluster runs Community Edition")
+		return
+	}
+
+	r.asConn = asConn
+}
+
+func (r *AerospikeXdrFilter) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Creating aerospike_xdr_filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeXdrFilterModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setFilter(ctx, data.Dc.ValueString(), data.Namespace.ValueString(), data.Expression.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "set xdr filter for dc "+data.Dc.ValueString()+" namespace "+data.Namespace.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeXdrFilter) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data AerospikeXdrFilterModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dc := data.Dc.ValueString()
+	namespace := data.Namespace.ValueString()
+
+	res, err := r.asConn.infoAnyNode(ctx, xdrGetFilterCommand(dc, namespace))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading xdr filter", err.Error())
+		return
+	}
+
+	expression := parseXdrFilter(res, namespace)
+	if isInfoError(res) || expression == "" {
+		tflog.Trace(ctx, "no xdr filter for dc "+dc+" namespace "+namespace)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	data.Expression = types.StringValue(expression)
+
+	tflog.Trace(ctx, "read xdr filter for dc "+dc+" namespace "+namespace)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeXdrFilter) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Updating aerospike_xdr_filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeXdrFilterModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setFilter(ctx, data.Dc.ValueString(), data.Namespace.ValueString(), data.Expression.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AerospikeXdrFilter) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.asConn.checkWritable("Deleting aerospike_xdr_filter")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data AerospikeXdrFilterModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.setFilter(ctx, data.Dc.ValueString(), data.Namespace.ValueString(), "null")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "removed xdr filter for dc "+data.Dc.ValueString()+" namespace "+data.Namespace.ValueString())
+}
+
+// ImportState expects an id of the form dc:namespace.
+func (r *AerospikeXdrFilter) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	dc, namespace, ok := strings.Cut(req.ID, ":")
+	if !ok || dc == "" || namespace == "" {
+		resp.Diagnostics.AddError("Invalid import id", "Expected dc:namespace, got "+req.ID)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dc"), dc)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespace)...)
+}
+
+// setFilter installs the expression on all nodes. The expression "null" removes the filter.
+func (r *AerospikeXdrFilter) setFilter(ctx context.Context, dc, namespace, expression string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	responses, err := r.asConn.infoAllNodes(ctx, xdrSetFilterCommand(dc, namespace, expression))
+	if err != nil {
+		diags.AddError("Error setting xdr filter", err.Error())
+		return diags
+	}
+
+	for _, node := range sortedKeys(responses) {
+		if isInfoError(responses[node]) {
+			diags.AddError("Error setting xdr filter",
+				fmt.Sprintf("Node %s returned %q. Check that the dc and namespace exist and the expression is valid", node, responses[node]))
+		}
+	}
+
+	return diags
+}
+
+func xdrSetFilterCommand(dc, namespace, expression string) string {
+	return "xdr-set-filter:dc=" + dc + ";namespace=" + namespace + ";exp=" + expression
+}
+
+func xdrGetFilterCommand(dc, namespace string) string {
+	return "xdr-get-filter:dc=" + dc + ";namespace=" + namespace
+}
+
+// parseXdrFilter returns the expression of the namespace from an xdr-get-filter response of the form
+// "namespace=test:exp=kxGRSCr6", or "" if the namespace has no filter.
+func parseXdrFilter(response, namespace string) string {
+	for _, entry := range strings.Split(strings.TrimSpace(response), ";") {
+		fields := make(map[string]string)
+		for _, kv := range strings.Split(entry, ":") {
+			k, v, _ := strings.Cut(kv, "=")
+			fields[k] = v
+		}
+		if fields["namespace"] == namespace && fields["exp"] != "null" {
+			return fields["exp"]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestXdrFilterCommands(t *testing.T) {
+	if got, want := xdrSetFilterCommand("dc1", "test", "kxGRSCr6"), "xdr-set-filter:dc=dc1;namespace=test;exp=kxGRSCr6"; got != want {
+		t.Errorf("xdrSetFilterCommand() = %q, want %q", got, want)
+	}
+	if got, want := xdrGetFilterCommand("dc1", "test"), "xdr-get-filter:dc=dc1;namespace=test"; got != want {
+		t.Errorf("xdrGetFilterCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestParseXdrFilter(t *testing.T) {
+	cases := []struct {
+		response, namespace, want string
+	}{
+		{"namespace=test:exp=kxGRSCr6", "test", "kxGRSCr6"},
+		{"namespace=bar:exp=AAAA;namespace=test:exp=kxGRSCr6=\n", "test", "kxGRSCr6="},
+		{"namespace=test:exp=null", "test", ""},
+		{"namespace=bar:exp=AAAA", "test", ""},
+		{"", "test", ""},
+	}
+
+	for _, c := range cases {
+		if got := parseXdrFilter(c.response, c.namespace); got != c.want {
+			t.Errorf("parseXdrFilter(%q, %q) = %q, want %q", c.response, c.namespace, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// retryableErrorClasses are the error classes retry_policy can retry, and the result codes of each.
+var retryableErrorClasses = map[string][]astypes.ResultCode{
+	"timeout": {astypes.TIMEOUT},
+	"network": {astypes.NETWORK_ERROR, astypes.NO_AVAILABLE_CONNECTIONS_TO_NODE, astypes.SERVER_NOT_AVAILABLE},
+	"busy":    {astypes.DEVICE_OVERLOAD, astypes.KEY_BUSY},
+	"quota":   {astypes.QUOTA_EXCEEDED},
+}
+
+// defaultRetryableErrors are the error classes retried when retry_policy doesn't list them.
+var defaultRetryableErrors = []string{"timeout", "network"}
+
+const (
+	// defaultRetryBaseBackoff and defaultRetryMaxBackoff are used when retry_policy doesn't set the backoff.
+	defaultRetryBaseBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = 5 * time.Second
+)
+
+type AerospikeRetryPolicyModel struct {
+	Max_retries      types.Int64    `tfsdk:"max_retries"`
+	Base_backoff     types.String   `tfsdk:"base_backoff"`
+	Max_backoff      types.String   `tfsdk:"max_backoff"`
+	Retryable_errors []types.String `tfsdk:"retryable_errors"`
+}
+
+// retryPolicy retries the commands of all resources and data sources that fail with a retryable error, waiting
+// twice as long before every retry. A nil policy doesn't retry.
+type retryPolicy struct {
+	maxRetries  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	resultCodes []astypes.ResultCode
+}
+
+// newRetryPolicy returns the retry policy of the retry_policy block. The error returned names the invalid attribute.
+func newRetryPolicy(data AerospikeRetryPolicyModel) (*retryPolicy, string, error) {
+	p := &retryPolicy{
+		maxRetries:  int(data.Max_retries.ValueInt64()),
+		baseBackoff: defaultRetryBaseBackoff,
+		maxBackoff:  defaultRetryMaxBackoff,
+	}
+
+	var err error
+	if !data.Base_backoff.IsNull() {
+		if p.baseBackoff, err = time.ParseDuration(data.Base_backoff.ValueString()); err != nil || p.baseBackoff <= 0 {
+			return nil, "base_backoff", fmt.Errorf("base_backoff must be a positive duration such as \"100ms\", got %s", data.Base_backoff.ValueString())
+		}
+	}
+	if !data.Max_backoff.IsNull() {
+		if p.maxBackoff, err = time.ParseDuration(data.Max_backoff.ValueString()); err != nil || p.maxBackoff <= 0 {
+			return nil, "max_backoff", fmt.Errorf("max_backoff must be a positive duration such as \"5s\", got %s", data.Max_backoff.ValueString())
+		}
+	}
+	if p.maxBackoff < p.baseBackoff {
+		return nil, "max_backoff", fmt.Errorf("max_backoff (%s) can't be shorter than base_backoff (%s)", p.maxBackoff, p.baseBackoff)
+	}
+
+	classes := defaultRetryableErrors
+	if data.Retryable_errors != nil {
+		classes = make([]string, 0, len(data.Retryable_errors))
+		for _, c := range data.Retryable_errors {
+			classes = append(classes, c.ValueString())
+		}
+	}
+	for _, c := range classes {
+		p.resultCodes = append(p.resultCodes, retryableErrorClasses[c]...)
+	}
+
+	return p, "", nil
+}
+
+// retryable reports whether a command that failed with err should be retried.
+func (p *retryPolicy) retryable(err as.Error) bool {
+	return p != nil && err != nil && len(p.resultCodes) > 0 && err.Matches(p.resultCodes...)
+}
+
+// backoff returns the time to wait before retry number attempt, counting from 0.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.baseBackoff
+	for i := 0; i < attempt && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, p.maxBackoff)
+}
+
+// retry runs op until it succeeds, fails with an error that isn't retryable or runs out of retries.
+func retry[T any](ctx context.Context, p *retryPolicy, op func() (T, as.Error)) (T, as.Error) {
+	result, err := op()
+	for attempt := 0; p.retryable(err) && attempt < p.maxRetries; attempt++ {
+		wait := p.backoff(attempt)
+		tflog.Info(ctx, fmt.Sprintf("retrying in %s after error: %s", wait, err))
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(wait):
+		}
+
+		result, err = op()
+	}
+
+	return result, err
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	astypes "github.com/aerospike/aerospike-client-go/v7/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	p, _, err := newRetryPolicy(AerospikeRetryPolicyModel{Max_retries: types.Int64Value(3)})
+	if err != nil {
+		t.Fatalf("newRetryPolicy() = %v", err)
+	}
+	if p.baseBackoff != defaultRetryBaseBackoff || p.maxBackoff != defaultRetryMaxBackoff {
+		t.Errorf("backoff = %s-%s, want the defaults", p.baseBackoff, p.maxBackoff)
+	}
+	if !p.retryable(fakeError(astypes.TIMEOUT)) || p.retryable(fakeError(astypes.KEY_BUSY)) {
+		t.Error("the default policy should retry timeouts and not busy keys")
+	}
+
+	_, attr, err := newRetryPolicy(AerospikeRetryPolicyModel{
+		Max_retries:  types.Int64Value(3),
+		Base_backoff: types.StringValue("2s"),
+		Max_backoff:  types.StringValue("1s"),
+	})
+	if err == nil || attr != "max_backoff" {
+		t.Errorf("newRetryPolicy() = %v on %q, want an error on max_backoff", err, attr)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &retryPolicy{baseBackoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second} {
+		if got := p.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	p := &retryPolicy{maxRetries: 2, baseBackoff: time.Millisecond, maxBackoff: time.Millisecond,
+		resultCodes: retryableErrorClasses["busy"]}
+
+	// succeeds on the second attempt
+	calls := 0
+	_, err := retry(context.Background(), p, func() (int, as.Error) {
+		calls++
+		if calls == 1 {
+			return 0, fakeError(astypes.KEY_BUSY)
+		}
+		return calls, nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("retry() = %v after %d calls, want success after 2", err, calls)
+	}
+
+	// gives up after max_retries
+	calls = 0
+	_, err = retry(context.Background(), p, func() (int, as.Error) {
+		calls++
+		return 0, fakeError(astypes.KEY_BUSY)
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("retry() = %v after %d calls, want an error after 3", err, calls)
+	}
+
+	// errors that aren't retryable fail at once
+	calls = 0
+	_, _ = retry(context.Background(), p, func() (int, as.Error) {
+		calls++
+		return 0, fakeError(astypes.INVALID_USER)
+	})
+	if calls != 1 {
+		t.Errorf("retry() made %d calls for an error that isn't retryable, want 1", calls)
+	}
+
+	// a nil policy doesn't retry
+	calls = 0
+	_, _ = retry(context.Background(), nil, func() (int, as.Error) {
+		calls++
+		return 0, fakeError(astypes.KEY_BUSY)
+	})
+	if calls != 1 {
+		t.Errorf("retry() with no policy made %d calls, want 1", calls)
+	}
+}
+
+// timeoutClient is a fake client whose creates and drops take effect but time out the first time.
+type timeoutClient struct {
+	*fakeClient
+	timedOut bool
+}
+
+func (c *timeoutClient) timeout(err as.Error) as.Error {
+	if err == nil && !c.timedOut {
+		c.timedOut = true
+		return fakeError(astypes.TIMEOUT)
+	}
+
+	return err
+}
+
+func (c *timeoutClient) CreateUser(policy *as.AdminPolicy, user string, password string, roles []string) as.Error {
+	return c.timeout(c.fakeClient.CreateUser(policy, user, password, roles))
+}
+
+func (c *timeoutClient) DropUser(policy *as.AdminPolicy, user string) as.Error {
+	return c.timeout(c.fakeClient.DropUser(policy, user))
+}
+
+func TestRetryUnique(t *testing.T) {
+	fake := newFakeClient()
+	conn := newFakeConnection(fake, "admin")
+	conn.retryPolicy = &retryPolicy{maxRetries: 2, baseBackoff: time.Millisecond, maxBackoff: time.Millisecond,
+		resultCodes: retryableErrorClasses["timeout"]}
+	client := &timeoutClient{fakeClient: fake}
+	r := &reloginClient{ctx: context.Background(), conn: conn, client: client}
+
+	// the create timed out after it took effect, the retry finds the user
+	if err := r.CreateUser(conn.adminPolicy, "u1", "pw", nil); err != nil {
+		t.Errorf("CreateUser() retried after a timeout = %v, want nil", err)
+	}
+	if want := []string{"CreateUser u1", "CreateUser u1"}; len(fake.calls) != 2 || fake.calls[0] != want[0] || fake.calls[1] != want[1] {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+
+	// without a timeout the user already existed
+	if err := r.CreateUser(conn.adminPolicy, "u1", "pw", nil); err == nil || !err.Matches(astypes.USER_ALREADY_EXISTS) {
+		t.Errorf("CreateUser() of an existing user = %v, want USER_ALREADY_EXISTS", err)
+	}
+
+	client.timedOut = false
+	if err := r.DropUser(conn.adminPolicy, "u1"); err != nil {
+		t.Errorf("DropUser() retried after a timeout = %v, want nil", err)
+	}
+	if _, ok := fake.users["u1"]; ok {
+		t.Errorf("user u1 wasn't dropped")
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	fake := newFakeClient()
+	fake.users["u1"] = &as.UserRoles{User: "u1"}
+	conn := newFakeConnection(fake, "admin")
+	conn.retryPolicy = &retryPolicy{maxRetries: 5, baseBackoff: time.Hour, maxBackoff: time.Hour,
+		resultCodes: retryableErrorClasses["timeout"]}
+
+	// terraform canceled the operation, the client gives up instead of waiting for the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &timeoutClient{fakeClient: fake}
+	conn.client = client
+	if err := conn.getClient(ctx).DropUser(conn.adminPolicy, "u1"); err == nil || !err.Matches(astypes.TIMEOUT) {
+		t.Errorf("DropUser() with a canceled context = %v, want the TIMEOUT error", err)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("calls = %v, want a single attempt", fake.calls)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	as "github.com/aerospike/aerospike-client-go/v7"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// sweepPrefixes are the name prefixes of the users and roles the acceptance tests create. Anything matching them is
+// removed by the sweepers, so don't use them for real users and roles on a test cluster.
+var sweepPrefixes = []string{"testuser", "testrole", "testds", "tf-tmp-"}
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// The provider has no secondary index or UDF resources, so the acceptance tests don't leave any behind to sweep.
+func init() {
+	resource.AddTestSweepers("aerospike_user", &resource.Sweeper{
+		Name: "aerospike_user",
+		F:    sweepUsers,
+	})
+	resource.AddTestSweepers("aerospike_role", &resource.Sweeper{
+		Name:         "aerospike_role",
+		F:            sweepRoles,
+		Dependencies: []string{"aerospike_user"},
+	})
+}
+
+// sweeperClient connects with the same environment variables the acceptance tests use.
+func sweeperClient() (as.ClientIfc, error) {
+	port, err := strconv.Atoi(os.Getenv("AEROSPIKE_PORT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AEROSPIKE_PORT: %w", err)
+	}
+
+	cp := as.NewClientPolicy()
+	cp.User = os.Getenv("AEROSPIKE_USER")
+	cp.Password = os.Getenv("AEROSPIKE_PASSWORD")
+
+	client, asErr := as.CreateClientWithPolicyAndHost(as.CTNative, cp, as.NewHost(os.Getenv("AEROSPIKE_HOST"), port))
+	if asErr != nil {
+		return nil, asErr
+	}
+
+	return client, nil
+}
+
+func isSweepable(name string) bool {
+	for _, prefix := range sweepPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sweepUsers(_ string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	adminPol := as.NewAdminPolicy()
+	users, asErr := client.QueryUsers(adminPol)
+	if asErr != nil {
+		return asErr
+	}
+
+	for _, u := range users {
+		if !isSweepable(u.User) || u.User == os.Getenv("AEROSPIKE_USER") {
+			continue
+		}
+		if asErr := client.DropUser(adminPol, u.User); asErr != nil {
+			return fmt.Errorf("error dropping user %s: %w", u.User, asErr)
+		}
+	}
+
+	return nil
+}
+
+func sweepRoles(_ string) error {
+	client, err := sweeperClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	adminPol := as.NewAdminPolicy()
+	roles, asErr := client.QueryRoles(adminPol)
+	if asErr != nil {
+		return asErr
+	}
+
+	for _, r := range roles {
+		if !isSweepable(r.Name) {
+			continue
+		}
+		if asErr := client.DropRole(adminPol, r.Name); asErr != nil {
+			return fmt.Errorf("error dropping role %s: %w", r.Name, asErr)
+		}
+	}
+
+	return nil
+}
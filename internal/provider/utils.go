@@ -4,10 +4,52 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// maxSecurityNameLength is the longest user or role name the server accepts.
+const maxSecurityNameLength = 63
+
+// securityNameRegexp matches the user and role names the server accepts. Control characters are rejected, ':' and ';'
+// separate fields in the info commands that report users and roles.
+var securityNameRegexp = regexp.MustCompile(`^[^\x00-\x1f\x7f:;]+$`)
+
+// maxPasswordLength is the longest password that is fully used. Passwords are hashed with bcrypt, which ignores
+// everything past the first 72 bytes.
+const maxPasswordLength = 72
+
+// passwordRegexp rejects control characters, which can't be typed at a login prompt.
+var passwordRegexp = regexp.MustCompile(`^[^\x00-\x1f\x7f]+$`)
+
+// passwordValidators check passwords against the server limits at plan time, so CreateUser doesn't fail mid-apply.
+func passwordValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.LengthBetween(1, maxPasswordLength),
+		stringvalidator.RegexMatches(passwordRegexp, "must not contain control characters"),
+	}
+}
+
+// securityNameValidators check user and role names against the server limits, so a typo fails the plan instead of
+// failing halfway through an apply.
+func securityNameValidators(extra ...validator.String) []validator.String {
+	return append([]validator.String{
+		stringvalidator.LengthBetween(1, maxSecurityNameLength),
+		stringvalidator.RegexMatches(securityNameRegexp, "must not contain control characters, ':' or ';'"),
+	}, extra...)
+}
+
 func withEnvironmentOverrideString(currentValue, envOverrideKey string) string {
 	envValue, ok := os.LookupEnv(envOverrideKey)
 	if ok {
@@ -28,3 +70,143 @@ func withEnvironmentOverrideInt64(currentValue int64, envOverrideKey string) int
 
 	return currentValue
 }
+
+// nameMatcher returns a function matching names against an optional prefix and an optional regular expression.
+func nameMatcher(prefix, regex string) (func(string) bool, error) {
+	var re *regexp.Regexp
+	if regex != "" {
+		var err error
+		re, err = regexp.Compile(regex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(name string) bool {
+		if !strings.HasPrefix(name, prefix) {
+			return false
+		}
+		return re == nil || re.MatchString(name)
+	}, nil
+}
+
+// stringValueOrDefault returns the value of v, or defaultValue if v is null.
+func stringValueOrDefault(v types.String, defaultValue string) string {
+	if v.IsNull() {
+		return defaultValue
+	}
+
+	return v.ValueString()
+}
+
+// int64ValueOrDefault returns the value of v, or defaultValue if v is null.
+func int64ValueOrDefault(v types.Int64, defaultValue int64) int64 {
+	if v.IsNull() {
+		return defaultValue
+	}
+
+	return v.ValueInt64()
+}
+
+// sameStrings reports whether a and b hold the same strings, ignoring order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitHost splits a host that may include a port, such as "db1:3000" or "[::1]:3000", and removes the brackets
+// of IPv6 literals. A port in host overrides port, and both must agree when both are set. IPv6 literals without
+// brackets are returned as is, since their last group can't be told apart from a port.
+func splitHost(host string, port int64) (string, int64, error) {
+	if !strings.HasPrefix(host, "[") && strings.Count(host, ":") != 1 {
+		return host, port, nil
+	}
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		return strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), port, nil
+	}
+
+	name, portString, err := net.SplitHostPort(host)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid host %q: %w", host, err)
+	}
+	hostPort, err := strconv.ParseInt(portString, 10, 64)
+	if err != nil || hostPort < 1 || hostPort > 65535 {
+		return "", 0, fmt.Errorf("invalid port %q in host %q", portString, host)
+	}
+	if port != 0 && port != hostPort {
+		return "", 0, fmt.Errorf("host %q has port %d but port is set to %d", host, hostPort, port)
+	}
+
+	return name, hostPort, nil
+}
+
+// resolveWhiteList replaces the host names in a white list with the addresses lookup returns for them. IP addresses
+// and CIDR ranges are kept as is. The result is sorted and has no duplicates.
+func resolveWhiteList(ctx context.Context, entries []string, lookup func(context.Context, string) ([]string, error)) ([]string, error) {
+	seen := make(map[string]bool)
+	resolved := make([]string, 0, len(entries))
+	add := func(address string) {
+		if !seen[address] {
+			seen[address] = true
+			resolved = append(resolved, address)
+		}
+	}
+
+	for _, e := range entries {
+		if net.ParseIP(e) != nil {
+			add(e)
+			continue
+		}
+		if _, _, err := net.ParseCIDR(e); err == nil {
+			add(e)
+			continue
+		}
+
+		addresses, err := lookup(ctx, e)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve white list entry %q: %w", e, err)
+		}
+		for _, a := range addresses {
+			add(a)
+		}
+	}
+	sort.Strings(resolved)
+
+	return resolved, nil
+}
+
+// stringSet converts a list of strings to a terraform set.
+func stringSet(values []string) types.Set {
+	elements := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, types.StringValue(v))
+	}
+
+	return types.SetValueMust(types.StringType, elements)
+}
+
+// setStrings returns the values of a known set of strings.
+func setStrings(set types.Set) []string {
+	values := make([]string, 0, len(set.Elements()))
+	for _, e := range set.Elements() {
+		if s, ok := e.(types.String); ok {
+			values = append(values, s.ValueString())
+		}
+	}
+
+	return values
+}
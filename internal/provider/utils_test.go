@@ -0,0 +1,97 @@
+// Copyright (c) Harel Safra
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		port     int64
+		wantHost string
+		wantPort int64
+		wantErr  bool
+	}{
+		{host: "db1", port: 3000, wantHost: "db1", wantPort: 3000},
+		{host: "db1:3100", wantHost: "db1", wantPort: 3100},
+		{host: "db1:3100", port: 3100, wantHost: "db1", wantPort: 3100},
+		{host: "db1:3100", port: 3000, wantErr: true},
+		{host: "db1:port", wantErr: true},
+		{host: "db1:70000", wantErr: true},
+		{host: "[::1]:3000", wantHost: "::1", wantPort: 3000},
+		{host: "[::1]", port: 3000, wantHost: "::1", wantPort: 3000},
+		{host: "fe80::1", port: 3000, wantHost: "fe80::1", wantPort: 3000},
+		{host: "[fe80::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			host, port, err := splitHost(tt.host, tt.port)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (host != tt.wantHost || port != tt.wantPort) {
+				t.Errorf("splitHost() = %q, %d, want %q, %d", host, port, tt.wantHost, tt.wantPort)
+			}
+		})
+	}
+}
+
+func TestResolveWhiteList(t *testing.T) {
+	lookup := func(ctx context.Context, host string) ([]string, error) {
+		switch host {
+		case "clients.example.com":
+			return []string{"10.0.0.2", "10.0.0.1"}, nil
+		case "app.example.com":
+			return []string{"10.0.0.1"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	got, err := resolveWhiteList(context.Background(), []string{"clients.example.com", "192.168.0.0/16", "app.example.com", "10.0.0.3"}, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "192.168.0.0/16"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveWhiteList() = %v, want %v", got, want)
+	}
+
+	if _, err := resolveWhiteList(context.Background(), []string{"missing.example.com"}, lookup); err == nil {
+		t.Error("resolveWhiteList() expected an error for an unresolvable host")
+	}
+}
+
+func TestSecurityNameRegexp(t *testing.T) {
+	for name, want := range map[string]bool{
+		"app-user":      true,
+		"svc.reader@ns": true,
+		"two words":     true,
+		"bad:name":      false,
+		"bad;name":      false,
+		"tab\tname":     false,
+	} {
+		if got := securityNameRegexp.MatchString(name); got != want {
+			t.Errorf("securityNameRegexp.MatchString(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestPasswordRegexp(t *testing.T) {
+	for password, want := range map[string]bool{
+		"Secret-123!":   true,
+		"with space":    true,
+		"new\nline":     false,
+		"nul\x00inside": false,
+	} {
+		if got := passwordRegexp.MatchString(password); got != want {
+			t.Errorf("passwordRegexp.MatchString(%q) = %v, want %v", password, got, want)
+		}
+	}
+}